@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/toasterlint/DAWS/common/construction"
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/parcels"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// construct break ground on whatever the city needs once a simulated day
+func construct(cityID bson.ObjectId) {
+	if !demographics.NewDay(settings.LastTime) {
+		return
+	}
+	city, err := store.GetCity(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load city")
+	buildings, err := store.GetBuildings(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load buildings")
+	residents, err := store.GetResidents(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load residents")
+
+	highways, err := store.GetAllHighways()
+	FailOnError(err, "Failed to load highways")
+	land := parcels.New(city, buildings, highways)
+
+	rng := random.ForEntity(settings, cityID, "construction")
+	for _, buildingType := range construction.Demand(rng, buildings, residents, settings.LastTime) {
+		project := construction.Plan(rng, buildingType)
+		count := 1
+		for _, b := range buildings {
+			if b.Type == buildingType {
+				count++
+			}
+		}
+		project.Name = construction.Name(project, count)
+		ready := settings.LastTime.Add(project.Duration)
+		building, ok := construction.Place(rng, land, city, project, ready)
+		if !ok {
+			Logger.Printf("%s: no land left to build %s", city.Name, project.Name)
+			continue
+		}
+		err = store.CreateBuilding(building)
+		FailOnError(err, "Failed to create building")
+		buildings = append(buildings, building)
+		Logger.Printf("%s: construction started on %s, ready %s", city.Name, building.Name, ready.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	. "image"
+
+	"github.com/toasterlint/DAWS/common/crime"
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/police"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// commitCrimes roll this tick's violent crimes and murders for a city and
+// send the police
+func commitCrimes(cityID bson.ObjectId) {
+	population, err := store.GetResidentsCount(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to count residents")
+	rng := random.ForEntity(settings, cityID, "crime")
+	crimes := crime.Sample(rng, settings, population)
+	if len(crimes) == 0 {
+		return
+	}
+
+	all, err := store.GetResidents(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load residents")
+	// travelers are the traffic worker's this tick, so they can't be victims
+	residents := []commonModels.Person{}
+	for _, r := range all {
+		if !r.Traveling {
+			residents = append(residents, r)
+		}
+	}
+	recent, err := store.GetCrimes(Mongoid{ID: cityID}, settings.LastTime.Add(-police.ARRESTMEMORY))
+	FailOnError(err, "Failed to load recent crimes")
+	deterrence := func(location Point) float64 {
+		return police.Deterrence(recent, location, settings.LastTime)
+	}
+	for _, crimeType := range crimes {
+		event, victim, ok := crime.Commit(rng, crimeType, cityID, residents, settings.LastTime, deterrence)
+		if !ok {
+			continue
+		}
+		// only the harm is saved, a victim who has been dispatched or died
+		// since the residents were loaded is left alone
+		err = store.HarmPerson(residents[victim])
+		if err == mgo.ErrNotFound {
+			continue
+		}
+		FailOnError(err, "Failed to update victim")
+		dispatch(&event, residents[victim].ID)
+		err = store.CreateCrime(event)
+		FailOnError(err, "Failed to record crime")
+		if event.Type == commonModels.Homicide {
+			LogToConsole("Murder: " + residents[victim].FirstName + " " + residents[victim].LastName + " was killed")
+		}
+	}
+}
+
+// dispatch send an officer from the nearest station with one on duty, never
+// the victim. The station is only a snapshot, so an officer is skipped if
+// they have left or been sent elsewhere since.
+func dispatch(event *commonModels.Crime, victimID bson.ObjectId) {
+	buildings, err := store.GetBuildings(Mongoid{ID: event.CityID})
+	FailOnError(err, "Failed to load buildings")
+	for _, station := range geometry.Nearest(police.Stations(buildings, settings.LastTime), event.Location) {
+		occupants, err := store.GetPeopleInBuilding(Mongoid{ID: station.ID})
+		FailOnError(err, "Failed to load station")
+		for _, i := range police.Available(station, occupants) {
+			officer := occupants[i]
+			if officer.ID == victimID {
+				continue
+			}
+			call := *event
+			police.Dispatch(&officer, &call, station, settings.LastTime)
+			err = store.DispatchOfficer(officer, Mongoid{ID: station.ID})
+			if err == mgo.ErrNotFound {
+				continue
+			}
+			FailOnError(err, "Failed to dispatch officer")
+			*event = call
+			return
+		}
+	}
+	LogToConsole("No police available to respond to crime " + event.ID.Hex())
+}
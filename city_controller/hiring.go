@@ -0,0 +1,67 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/jobs"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/roads"
+	"github.com/toasterlint/DAWS/common/traffic"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// hire match the city's residents who are out of work to the nearest jobs
+// they qualify for. It runs once a day for the whole city before any building
+// jobs go out, so every slot is handed out once and nobody else is saving the
+// people hired. Travelers are left for another day since their traffic worker
+// may be saving them.
+func hire(cityID bson.ObjectId) {
+	if !demographics.NewDay(settings.LastTime) {
+		return
+	}
+	now := settings.LastTime
+	residents, err := store.GetResidents(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load residents")
+	buildings, err := store.GetBuildings(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load buildings")
+	homes := map[bson.ObjectId]commonModels.Building{}
+	for _, b := range buildings {
+		homes[b.ID] = b
+	}
+
+	var vacancies []jobs.Vacancy
+	var graph *roads.Graph
+	for i := range residents {
+		person := &residents[i]
+		if person.Traveling || jobs.Employed(*person) || !jobs.Workforce(*person, now) {
+			continue
+		}
+		home, ok := homes[person.HomeBuilding]
+		if !ok {
+			continue
+		}
+		if vacancies == nil {
+			vacancies, err = jobs.Vacancies(buildings, now, func(b commonModels.Building) (int, error) {
+				return store.GetEmployeesCount(Mongoid{ID: b.ID})
+			})
+			FailOnError(err, "Failed to count employees")
+		}
+		v := jobs.Match(*person, vacancies, home.TopLeft)
+		if v < 0 {
+			continue
+		}
+		jobs.Hire(person, &vacancies[v])
+		if graph == nil {
+			city, err := store.GetCity(Mongoid{ID: cityID})
+			FailOnError(err, "Failed to load city")
+			graph = traffic.NewGraph(settings, []commonModels.City{city}, nil)
+		}
+		if commute := traffic.CommuteMinutes(graph, home.TopLeft, vacancies[v].Building.TopLeft); commute > 0 {
+			person.Schedule.Commute = commute + 5
+		}
+		err = store.UpdatePerson(*person)
+		FailOnError(err, "Failed to update new hire")
+		Logger.Printf("%s %s was hired at %s", person.FirstName, person.LastName, vacancies[v].Building.Name)
+	}
+}
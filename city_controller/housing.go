@@ -0,0 +1,102 @@
+package main
+
+import (
+	"time"
+
+	"github.com/toasterlint/DAWS/common/construction"
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/economy"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// relocate move households out of the city's overcrowded homes into the
+// nearest open home with room for them. Like hire it runs once a day for the
+// whole city before any building jobs go out, so room is only handed out
+// once. Households with someone traveling stay put until another day.
+func relocate(cityID bson.ObjectId) {
+	if !demographics.NewDay(settings.LastTime) {
+		return
+	}
+	now := settings.LastTime
+	residents, err := store.GetResidents(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load residents")
+	buildings, err := store.GetBuildings(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load buildings")
+	homes := construction.Homes(buildings, now)
+	room := map[bson.ObjectId]int{}
+	for _, h := range homes {
+		room[h.ID] = h.MaxOccupancy
+	}
+	index := map[bson.ObjectId]int{}
+	for i := range residents {
+		room[residents[i].HomeBuilding]--
+		index[residents[i].ID] = i
+	}
+
+	for _, building := range homes {
+		if economy.Rent(building) == 0 || room[building.ID] >= 0 {
+			continue
+		}
+		var nearest []commonModels.Building
+		for i := range residents {
+			person := residents[i]
+			if room[building.ID] >= 0 {
+				break
+			}
+			if person.HomeBuilding != building.ID || person.Traveling || !demographics.Adult(person, now) {
+				continue
+			}
+			household, ok := householdOf(residents, index, i, now)
+			if !ok {
+				continue
+			}
+			if nearest == nil {
+				nearest = geometry.Nearest(homes, building.TopLeft)
+			}
+			for _, h := range nearest {
+				if h.ID == building.ID || room[h.ID] < len(household) {
+					continue
+				}
+				for _, j := range household {
+					residents[j].HomeBuilding = h.ID
+					err = store.UpdatePerson(residents[j])
+					FailOnError(err, "Failed to update relocated resident")
+				}
+				room[h.ID] -= len(household)
+				room[building.ID] += len(household)
+				Logger.Printf("%s %s moved to %s with a household of %d", person.FirstName, person.LastName, h.Name, len(household))
+				break
+			}
+		}
+	}
+}
+
+// householdOf the resident at i with their spouse and children living in the
+// same home, false if any of them is traveling
+func householdOf(residents []commonModels.Person, index map[bson.ObjectId]int, i int, now time.Time) ([]int, bool) {
+	person := residents[i]
+	household := []int{i}
+	if person.Spouse.Valid() {
+		if j, ok := index[person.Spouse]; ok && residents[j].HomeBuilding == person.HomeBuilding {
+			if residents[j].Traveling {
+				return nil, false
+			}
+			household = append(household, j)
+		}
+	}
+	for _, child := range person.ChildrenIDs {
+		j, ok := index[child]
+		if !ok || residents[j].HomeBuilding != person.HomeBuilding || demographics.Adult(residents[j], now) {
+			continue
+		}
+		if residents[j].Traveling {
+			return nil, false
+		}
+		household = append(household, j)
+	}
+	return household, true
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/economy"
+	"github.com/toasterlint/DAWS/common/jobs"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// recordStats save the city's figures at the start of a simulated day, before
+// the day's building jobs change anything
+func recordStats(cityID bson.ObjectId) {
+	if !demographics.NewDay(settings.LastTime) {
+		return
+	}
+	residents, err := store.GetResidents(Mongoid{ID: cityID})
+	FailOnError(err, "Failed to load residents")
+	stat := jobs.Stat(cityID, residents, settings.LastTime)
+	economy.Stat(&stat, residents, settings.LastTime)
+	err = store.SaveCityStat(stat)
+	FailOnError(err, "Failed to save city stats")
+	Logger.Printf("City %s: %d residents, %d of %d in work (%.1f%% unemployment), GDP $%d, median income $%d, %.1f%% in poverty",
+		cityID.Hex(), stat.Population, stat.Employed, stat.LaborForce, 100*jobs.UnemploymentRate(stat), stat.GDP, stat.MedianIncome, 100*stat.PovertyRate)
+}
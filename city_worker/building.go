@@ -0,0 +1,196 @@
+package main
+
+import (
+	"math/rand"
+
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/disease"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	"github.com/toasterlint/DAWS/common/schedule"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// MOODCHANCE chance per tick that an occupant's health or happiness shifts
+	MOODCHANCE = 1.0 / 600
+	// LEAVECHANCE chance per tick during free time that a content occupant heads somewhere else
+	LEAVECHANCE = 1.0 / 3600
+)
+
+// simulateBuilding run one tick for a building and everyone inside it
+func simulateBuilding(buildingID bson.ObjectId) {
+	building, err := store.GetBuilding(Mongoid{ID: buildingID})
+	if err != nil {
+		LogToConsole("Failed to load building " + buildingID.Hex() + ": " + err.Error())
+		return
+	}
+	occupants, err := store.GetPeopleInBuilding(Mongoid{ID: building.ID})
+	FailOnError(err, "Failed to load occupants")
+	if len(occupants) == 0 {
+		return
+	}
+	crowded := building.MaxOccupancy > 0 && len(occupants) > building.MaxOccupancy
+
+	changed := make([]bool, len(occupants))
+	dailyPass(building, occupants, changed)
+	if newHour() {
+		spreadIllness(building, occupants, changed)
+		admitAndDischarge(building, occupants, changed)
+		spendHour(building, occupants, changed)
+	}
+
+	var destinations []commonModels.Building
+	for i := range occupants {
+		person := &occupants[i]
+		if !demographics.Alive(*person) {
+			continue
+		}
+		rng := random.ForEntity(settings, person.ID, "occupant")
+		if tickOccupant(rng, person, crowded) {
+			changed[i] = true
+		}
+		target, activity := schedule.Target(*person, settings.LastTime)
+		if person.Hospital.Valid() {
+			target, activity = person.Hospital, schedule.Asleep
+		} else if disease.Detected(settings, *person, settings.LastTime) {
+			// the sick stay home
+			target, activity = person.HomeBuilding, schedule.Asleep
+		}
+		if target.Valid() && target != building.ID {
+			destination, err := store.GetBuilding(Mongoid{ID: target})
+			if err == nil {
+				depart(person, destination)
+				changed[i] = true
+			}
+		} else if activity == schedule.Free && rng.Float64() < leaveChance(person) {
+			if destinations == nil {
+				destinations = otherBuildings(building)
+			}
+			if len(destinations) > 0 {
+				depart(person, destinations[rng.Intn(len(destinations))])
+				changed[i] = true
+			}
+		}
+	}
+
+	for i := range occupants {
+		if changed[i] {
+			err = store.UpdatePerson(occupants[i])
+			FailOnError(err, "Failed to update occupant")
+		}
+	}
+}
+
+// dailyPass roll the day's events for the occupants who haven't had them yet
+// today, usually everyone on the first tick after midnight and later anyone
+// who was traveling then
+func dailyPass(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	due := []int{}
+	for i := range occupants {
+		if demographics.DailyDue(occupants[i], settings.LastTime) {
+			due = append(due, i)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	lethality := patientLethality(building, occupants)
+	today := make([]commonModels.Person, len(due))
+	for k, i := range due {
+		today[k] = occupants[i]
+		today[k].LastDay = demographics.Day(settings.LastTime)
+	}
+	todayChanged := make([]bool, len(today))
+	dailyLife(today, todayChanged)
+	dailyIllness(building, today, todayChanged, lethality)
+	careForPatients(building, today, todayChanged)
+	dailySchool(building, today, todayChanged)
+	dailyWork(building, today, todayChanged)
+	dailyMoney(building, today, todayChanged)
+	for k, i := range due {
+		occupants[i] = today[k]
+		changed[i] = true
+	}
+}
+
+// homeOf the building a person lives in, usually the one they're in now
+func homeOf(person commonModels.Person, building commonModels.Building) (commonModels.Building, bool) {
+	if person.HomeBuilding == building.ID {
+		return building, true
+	}
+	if !person.HomeBuilding.Valid() {
+		return commonModels.Building{}, false
+	}
+	home, err := store.GetBuilding(Mongoid{ID: person.HomeBuilding})
+	return home, err == nil
+}
+
+// tickOccupant adjust health and happiness of someone inside a building,
+// returning true if anything changed
+func tickOccupant(rng *rand.Rand, person *commonModels.Person, crowded bool) bool {
+	changed := false
+	if person.NewToBuilding {
+		person.NewToBuilding = false
+		changed = true
+	}
+	if rng.Float64() < MOODCHANCE && !disease.Ill(*person) {
+		// resting indoors slowly restores health
+		person.Health = clamp(person.Health + 1)
+		changed = true
+	}
+	if rng.Float64() < MOODCHANCE {
+		if crowded {
+			person.Happiness = clamp(person.Happiness - 1)
+		} else if rng.Intn(2) == 0 {
+			person.Happiness = clamp(person.Happiness + 1)
+		} else {
+			person.Happiness = clamp(person.Happiness - 1)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// leaveChance unhappy people are more restless
+func leaveChance(person *commonModels.Person) float64 {
+	return LEAVECHANCE * (2 - float64(person.Happiness)/100)
+}
+
+// otherBuildings every other open building in the same city
+func otherBuildings(building commonModels.Building) []commonModels.Building {
+	ids, err := store.GetAllBuildingIDs(Mongoid{ID: building.CityID})
+	FailOnError(err, "Failed to get Building IDs for city")
+	buildings := []commonModels.Building{}
+	for _, id := range ids {
+		if id.ID == building.ID {
+			continue
+		}
+		other, err := store.GetBuilding(id)
+		if err != nil || other.BuildDate.After(settings.LastTime) {
+			continue
+		}
+		buildings = append(buildings, other)
+	}
+	return buildings
+}
+
+// depart turn an occupant into a traveler headed to destination
+func depart(person *commonModels.Person, destination commonModels.Building) {
+	person.Traveling = true
+	person.NewToBuilding = false
+	person.Destination = destination.TopLeft
+	person.DestinationID = destination.ID
+}
+
+func clamp(value int) int {
+	if value < 0 {
+		return 0
+	}
+	if value > 100 {
+		return 100
+	}
+	return value
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	. "image"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/broker"
+	"github.com/toasterlint/DAWS/common/config"
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/disease"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/names"
+	"github.com/toasterlint/DAWS/common/random"
+	"github.com/toasterlint/DAWS/common/schedule"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TICKS long enough to cross midnight and the top of an hour
+const TICKS = 3600
+
+// START half an hour before midnight so the run has a daily pass
+var START = time.Date(2020, 3, 1, 23, 30, 0, 0, time.UTC)
+
+// outcome everything the run left in the store
+type outcome struct {
+	People []commonModels.Person
+	Stats  [][]commonModels.DiseaseStat
+}
+
+// newWorld a city with one of each building and a few families, all drawn
+// from the seed
+func newWorld(seed int64) (commonModels.Settings, commonModels.City, []bson.ObjectId) {
+	world := commonModels.Settings{
+		Seed:             seed,
+		LastTime:         START,
+		ViolentCrimeRate: 0.00381,
+		MurderRate:       0.000053,
+		Diseases:         disease.Defaults(seed),
+		NameDataset:      names.DEFAULTDATASET,
+		SpeedLimits:      []commonModels.SpeedLimit{{Location: "city", Value: 35}, {Location: "noncity", Value: 70}},
+	}
+	rng := random.New(seed, "test world")
+	city := commonModels.City{ID: random.ObjectID(rng), Name: "Testville", BottomRight: Point{X: 5280, Y: 5280}, Established: START}
+	err := store.CreateCity(city)
+	FailOnError(err, "Failed to create city")
+
+	kinds := []commonModels.BuildingType{commonModels.House, commonModels.Apartment, commonModels.Office, commonModels.Retail, commonModels.School, commonModels.Hospital, commonModels.Police}
+	buildings := []commonModels.Building{}
+	for i, kind := range kinds {
+		b := commonModels.Building{
+			ID:           random.ObjectID(rng),
+			Name:         "Building",
+			Type:         kind,
+			TopLeft:      Point{X: 300 * i, Y: 300 * (i % 3)},
+			BottomRight:  Point{X: 300*i + 208, Y: 300*(i%3) + 208},
+			BuildDate:    START.AddDate(-1, 0, 0),
+			Floors:       2,
+			MaxOccupancy: 40,
+			CityID:       city.ID,
+		}
+		err = store.CreateBuilding(b)
+		FailOnError(err, "Failed to create building")
+		buildings = append(buildings, b)
+	}
+
+	gen, err := names.NewGenerator(world.NameDataset, rng)
+	FailOnError(err, "Failed to load names")
+	people := []bson.ObjectId{}
+	for family := 0; family < 6; family++ {
+		home := buildings[family%2]
+		couple := []commonModels.Person{}
+		for _, gender := range []commonModels.Gender{commonModels.Male, commonModels.Female} {
+			p := commonModels.Person{
+				ID:              random.ObjectID(rng),
+				Gender:          gender,
+				FirstName:       gen.FirstName(gender),
+				LastName:        "Test",
+				Birthdate:       START.AddDate(-20-rng.Intn(50), 0, 0),
+				ChildrenIDs:     []bson.ObjectId{},
+				CurrentBuilding: buildings[rng.Intn(len(buildings))].ID,
+				HomeBuilding:    home.ID,
+				WorkBuilding:    buildings[2+rng.Intn(len(buildings)-2)].ID,
+				Education:       commonModels.Secondary,
+				Balance:         1000,
+				Health:          50 + rng.Intn(51),
+				Happiness:       50 + rng.Intn(51),
+				Schedule:        schedule.New(rng),
+				LastDay:         demographics.Day(START),
+			}
+			couple = append(couple, p)
+		}
+		couple[0].Spouse, couple[1].Spouse = couple[1].ID, couple[0].ID
+		for _, p := range couple {
+			err = store.CreatePerson(p)
+			FailOnError(err, "Failed to create person")
+			people = append(people, p.ID)
+		}
+	}
+	return world, city, people
+}
+
+// run the city's buildings through the city worker over the memory broker
+// for TICKS ticks, then read back everyone who was ever in the world
+func run(t *testing.T, seed int64) outcome {
+	store = NewMemoryDAO()
+	cfg = config.Config{BrokerURL: "memory://"}
+	connectQueues()
+	defer mq.Close()
+	go processMsgs()
+	doneq, err := mq.QueueDeclare(broker.DoneQueue(broker.CITYDONEQUEUE, "test"))
+	FailOnError(err, "Failed to declare done queue")
+	done, err := mq.Consume(doneq.Name)
+	FailOnError(err, "Failed to consume done queue")
+
+	world, city, people := newWorld(seed)
+	for tick := 0; tick < TICKS; tick++ {
+		ids, err := store.GetAllBuildingIDs(Mongoid{ID: city.ID})
+		FailOnError(err, "Failed to get building IDs")
+		for _, id := range ids {
+			msg, _ := json.Marshal(commonModels.CityWorkerQueueMessage{WorldSettings: world, BuildingID: id.ID, ReplyTo: doneq.Name})
+			err = mq.Publish(cityjobq.Name, msg)
+			FailOnError(err, "Failed to publish job")
+		}
+		for range ids {
+			d := <-done
+			d.Ack(false)
+		}
+		world.LastTime = world.LastTime.Add(time.Second)
+		world.Tick++
+	}
+
+	result := outcome{}
+	residents, err := store.GetResidents(Mongoid{ID: city.ID})
+	FailOnError(err, "Failed to load residents")
+	for _, r := range residents {
+		people = append(people, r.ID)
+	}
+	seen := map[bson.ObjectId]bool{}
+	for _, id := range people {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		p, err := store.GetPerson(Mongoid{ID: id})
+		if err != nil {
+			t.Fatalf("Failed to load person %s: %s", id.Hex(), err)
+		}
+		result.People = append(result.People, p)
+	}
+	for _, d := range world.Diseases {
+		stats, err := store.GetDiseaseStats(Mongoid{ID: d.ID})
+		FailOnError(err, "Failed to load disease stats")
+		result.Stats = append(result.Stats, stats)
+	}
+	return result
+}
+
+func TestSameSeedSameWorld(t *testing.T) {
+	Logger = log.New(ioutil.Discard, "", 0)
+	first := run(t, 1234)
+	second := run(t, 1234)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("two runs with the same seed and start time differ:\n%+v\n%+v", first, second)
+	}
+
+	midnight := demographics.Day(START.Add(TICKS * time.Second))
+	moved := 0
+	for _, p := range first.People {
+		if demographics.Alive(p) && p.LastDay.Before(midnight) && !p.Traveling {
+			t.Errorf("%s %s was in a building after midnight but had no daily pass", p.FirstName, p.LastName)
+		}
+		if p.Traveling {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Errorf("nobody left a building in %d ticks, the run didn't simulate anything", TICKS)
+	}
+}
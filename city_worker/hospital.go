@@ -0,0 +1,87 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/geometry"
+	"github.com/toasterlint/DAWS/common/healthcare"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	. "github.com/toasterlint/DAWS/common/utils"
+)
+
+// patientLethality scale on disease lethality for patients of this building
+// if it's a hospital, worse when it's over capacity
+func patientLethality(building commonModels.Building, occupants []commonModels.Person) float64 {
+	if building.Type != commonModels.Hospital {
+		return 1
+	}
+	patients := 0
+	for i := range occupants {
+		if occupants[i].Hospital == building.ID {
+			patients++
+		}
+	}
+	return healthcare.Lethality(healthcare.Beds(building), patients)
+}
+
+// careForPatients a day of bed rest for the hospital's patients
+func careForPatients(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	for i := range occupants {
+		if occupants[i].Hospital == building.ID && demographics.Alive(occupants[i]) {
+			healthcare.Treat(&occupants[i])
+			changed[i] = true
+		}
+	}
+}
+
+// admitAndDischarge send the sick and injured to the nearest hospital with a
+// free bed, or the nearest one if all are full, and send recovered patients home
+func admitAndDischarge(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	var hospitals []healthcare.Occupancy
+	for i := range occupants {
+		person := &occupants[i]
+		if person.Hospital.Valid() {
+			if healthcare.Discharged(*person) {
+				healthcare.Discharge(person)
+				changed[i] = true
+			}
+			continue
+		}
+		if !healthcare.NeedsCare(settings, *person, settings.LastTime) {
+			continue
+		}
+		if hospitals == nil {
+			hospitals = occupancy(building)
+		}
+		admitted := false
+		for h := range hospitals {
+			if hospitals[h].Patients < hospitals[h].Beds {
+				healthcare.Admit(person, hospitals[h].Hospital)
+				hospitals[h].Patients++
+				changed[i] = true
+				admitted = true
+				break
+			}
+		}
+		if !admitted && len(hospitals) > 0 {
+			// every bed is taken, the nearest hospital takes them anyway
+			healthcare.Admit(person, hospitals[0].Hospital)
+			hospitals[0].Patients++
+			changed[i] = true
+			LogToConsole("Hospitals full, " + hospitals[0].Hospital.Name + " is over capacity")
+		}
+	}
+}
+
+// occupancy hospitals of the building's city, nearest first
+func occupancy(building commonModels.Building) []healthcare.Occupancy {
+	buildings, err := store.GetBuildings(Mongoid{ID: building.CityID})
+	FailOnError(err, "Failed to load buildings")
+	hospitals := []healthcare.Occupancy{}
+	for _, h := range geometry.Nearest(healthcare.Hospitals(buildings, settings.LastTime), building.TopLeft) {
+		patients, err := store.GetPatientsCount(Mongoid{ID: h.ID})
+		FailOnError(err, "Failed to count patients")
+		hospitals = append(hospitals, healthcare.Occupancy{Hospital: h, Beds: healthcare.Beds(h), Patients: patients})
+	}
+	return hospitals
+}
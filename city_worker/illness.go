@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/disease"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// newHour whether this is the first tick of a simulated hour, when disease
+// spreads between people sharing a building
+func newHour() bool {
+	return settings.LastTime.Minute() == 0 && settings.LastTime.Second() == 0
+}
+
+// spreadIllness an hour of contact between everyone in the building
+func spreadIllness(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	rng := random.ForEntity(settings, building.ID, "contact")
+	stats := map[bson.ObjectId]*commonModels.DiseaseStat{}
+	for _, i := range disease.Spread(rng, settings, occupants, settings.LastTime) {
+		changed[i] = true
+		stat(stats, occupants[i].Illness).NewCases++
+	}
+	recordStats(stats)
+}
+
+// dailyIllness progress everyone's illness by a day and roll for new ones.
+// Patients of this building get the hospital's lethality.
+func dailyIllness(building commonModels.Building, occupants []commonModels.Person, changed []bool, lethality float64) {
+	now := settings.LastTime
+	stats := map[bson.ObjectId]*commonModels.DiseaseStat{}
+	for i := range occupants {
+		person := &occupants[i]
+		if !demographics.Alive(*person) {
+			continue
+		}
+		rng := random.ForEntity(settings, person.ID, "illness")
+		if disease.Ill(*person) {
+			scale := 1.0
+			if person.Hospital == building.ID {
+				scale = lethality
+			}
+			d, outcome := disease.Progress(rng, settings, person, now, scale)
+			changed[i] = true
+			switch outcome {
+			case disease.Recovered:
+				stat(stats, d.ID).Recoveries++
+			case disease.Died:
+				stat(stats, d.ID).Deaths++
+				Logger.Printf("%s %s died of %s", person.FirstName, person.LastName, d.Name)
+			case disease.Unknown:
+				Logger.Printf("%s %s had unknown disease %s, dropping it", person.FirstName, person.LastName, d.ID.Hex())
+			}
+			continue
+		}
+		if d, ok := disease.Onset(rng, settings, person, now); ok {
+			changed[i] = true
+			stat(stats, d.ID).NewCases++
+		}
+	}
+	recordStats(stats)
+}
+
+func stat(stats map[bson.ObjectId]*commonModels.DiseaseStat, id bson.ObjectId) *commonModels.DiseaseStat {
+	if _, ok := stats[id]; !ok {
+		stats[id] = &commonModels.DiseaseStat{DiseaseID: id, Day: disease.Day(settings.LastTime)}
+	}
+	return stats[id]
+}
+
+func recordStats(stats map[bson.ObjectId]*commonModels.DiseaseStat) {
+	for _, s := range stats {
+		err := store.RecordDiseaseStat(*s)
+		FailOnError(err, "Failed to record disease stats")
+	}
+}
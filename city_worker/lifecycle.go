@@ -0,0 +1,83 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/names"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// dailyLife roll natural deaths and births for people having their daily
+// pass, marking who changed. A birth is only saved on the mother's side, the
+// father picks the child up on his own daily pass so nobody else writes him.
+func dailyLife(occupants []commonModels.Person, changed []bool) {
+	now := settings.LastTime
+	index := map[bson.ObjectId]int{}
+	for i := range occupants {
+		index[occupants[i].ID] = i
+	}
+	for i := range occupants {
+		person := &occupants[i]
+		rng := random.ForEntity(settings, person.ID, "demographics")
+		if demographics.DiesNaturally(rng, *person, now) {
+			demographics.Die(person, now, commonModels.Natural)
+			changed[i] = true
+			Logger.Printf("%s %s died of natural causes aged %d", person.FirstName, person.LastName, demographics.Age(*person, now))
+			continue
+		}
+		if reconcileChildren(person) {
+			changed[i] = true
+		}
+		if person.Gender != commonModels.Female || !person.Spouse.Valid() {
+			continue
+		}
+
+		// only a copy of the father, he is saved by whoever is simulating him
+		var father commonModels.Person
+		if j, here := index[person.Spouse]; here {
+			father = occupants[j]
+		} else {
+			spouse, err := store.GetPerson(Mongoid{ID: person.Spouse})
+			if err != nil {
+				continue
+			}
+			father = spouse
+		}
+		if !demographics.HasChild(rng, *person, father, now) {
+			continue
+		}
+
+		nameGen, err := names.NewGenerator(settings.NameDataset, rng)
+		FailOnError(err, "Failed to load names")
+		child := demographics.Child(rng, nameGen, person, &father, now)
+		err = store.CreatePerson(child)
+		FailOnError(err, "Failed to create child")
+		changed[i] = true
+		Logger.Printf("%s %s was born to %s and %s %s", child.FirstName, child.LastName, person.FirstName, father.FirstName, father.LastName)
+	}
+}
+
+// reconcileChildren add any children recorded against the person that they don't
+// know about yet, returning true if there were any
+func reconcileChildren(person *commonModels.Person) bool {
+	if !person.Spouse.Valid() {
+		return false
+	}
+	children, err := store.GetChildren(Mongoid{ID: person.ID})
+	FailOnError(err, "Failed to load children")
+	known := map[bson.ObjectId]bool{}
+	for _, id := range person.ChildrenIDs {
+		known[id] = true
+	}
+	found := false
+	for _, child := range children {
+		if !known[child.ID] {
+			person.ChildrenIDs = append(person.ChildrenIDs, child.ID)
+			found = true
+		}
+	}
+	return found
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/economy"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// dailyMoney pay people their wages out of their employer's city, charge the
+// rent on their home to its city and let their finances shift their mood
+func dailyMoney(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	ledger := map[bson.ObjectId]int{}
+	defer settle(ledger)
+	for i := range occupants {
+		person := &occupants[i]
+		if !demographics.Alive(*person) {
+			continue
+		}
+		home, ok := homeOf(*person, building)
+		if !ok {
+			continue
+		}
+		wage, rent := economy.Payday(person, home, settings.LastTime)
+		if wage > 0 {
+			ledger[employerCity(*person, building, home)] -= wage
+		}
+		ledger[home.CityID] += rent
+		person.Happiness = clamp(person.Happiness + economy.Mood(*person, home, settings.LastTime))
+		changed[i] = true
+	}
+}
+
+// spendHour visitors to shops and entertainment spend money and enjoy it
+func spendHour(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	if economy.Price(building) == 0 {
+		return
+	}
+	ledger := map[bson.ObjectId]int{}
+	defer settle(ledger)
+	for i := range occupants {
+		person := &occupants[i]
+		if demographics.Alive(*person) && economy.Spend(person, building) {
+			ledger[building.CityID] += economy.Price(building)
+			person.Happiness = clamp(person.Happiness + 1)
+			changed[i] = true
+		}
+	}
+}
+
+// employerCity the city whose balance pays a person's wage, the city of their
+// workplace or of their home if it can't be found
+func employerCity(person commonModels.Person, building commonModels.Building, home commonModels.Building) bson.ObjectId {
+	switch person.WorkBuilding {
+	case building.ID:
+		return building.CityID
+	case home.ID:
+		return home.CityID
+	}
+	if work, err := store.GetBuilding(Mongoid{ID: person.WorkBuilding}); err == nil {
+		return work.CityID
+	}
+	return home.CityID
+}
+
+// settle move the money a job's people paid and were paid into and out of
+// the cities' balances
+func settle(ledger map[bson.ObjectId]int) {
+	for cityID, amount := range ledger {
+		if amount == 0 || !cityID.Valid() {
+			continue
+		}
+		err := store.AdjustCityBalance(Mongoid{ID: cityID}, amount)
+		FailOnError(err, "Failed to update city balance")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/education"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+)
+
+// dailySchool enroll, move up and graduate children, picking schools near
+// their home
+func dailySchool(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	var schools []commonModels.Building
+	for i := range occupants {
+		person := &occupants[i]
+		home, ok := homeOf(*person, building)
+		if !ok {
+			continue
+		}
+		if schools == nil {
+			buildings, err := store.GetBuildings(Mongoid{ID: building.CityID})
+			FailOnError(err, "Failed to load buildings")
+			schools = education.Schools(buildings, settings.LastTime)
+		}
+		rng := random.ForEntity(settings, person.ID, "education")
+		level, enrolled := person.Education, person.School.Valid()
+		if !education.Daily(rng, person, schools, home.TopLeft, settings.LastTime) {
+			continue
+		}
+		changed[i] = true
+		switch {
+		case !enrolled && person.School.Valid():
+			Logger.Printf("%s %s started school", person.FirstName, person.LastName)
+		case person.Education != level:
+			Logger.Printf("%s %s finished %s", person.FirstName, person.LastName, education.Name(person.Education))
+		}
+	}
+}
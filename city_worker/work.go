@@ -0,0 +1,34 @@
+package main
+
+import (
+	"github.com/toasterlint/DAWS/common/jobs"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	. "github.com/toasterlint/DAWS/common/utils"
+)
+
+// dailyWork retire, fire and let go of workers. Hiring is done once a day for
+// the whole city by the city controller, so workers never compete for the
+// same slot.
+func dailyWork(building commonModels.Building, occupants []commonModels.Person, changed []bool) {
+	now := settings.LastTime
+	for i := range occupants {
+		person := &occupants[i]
+		if !jobs.Employed(*person) {
+			continue
+		}
+		rng := random.ForEntity(settings, person.ID, "jobs")
+		switch {
+		case !jobs.Workforce(*person, now):
+			Logger.Printf("%s %s retired", person.FirstName, person.LastName)
+		case jobs.Fired(rng):
+			Logger.Printf("%s %s was fired", person.FirstName, person.LastName)
+		case jobs.Quits(rng, *person):
+			Logger.Printf("%s %s quit their job", person.FirstName, person.LastName)
+		default:
+			continue
+		}
+		jobs.Leave(person)
+		changed[i] = true
+	}
+}
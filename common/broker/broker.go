@@ -0,0 +1,81 @@
+package broker
+
+import (
+	"strings"
+)
+
+// Queue names shared by the controllers and workers
+const (
+	// WORLDQUEUE controllers report their status to the world controller here
+	WORLDQUEUE = "world_queue"
+	// WORLDTRAFFICQUEUE world controller triggers traffic controllers here
+	WORLDTRAFFICQUEUE = "world_traffic_queue"
+	// WORLDCITYQUEUE world controller triggers city controllers here
+	WORLDCITYQUEUE = "world_city_queue"
+	// CITYJOBQUEUE city controllers queue building jobs for city workers here
+	CITYJOBQUEUE = "city_job_queue"
+	// TRAFFICJOBQUEUE traffic controllers queue traveler jobs for traffic workers here
+	TRAFFICJOBQUEUE = "traffic_job_queue"
+	// CITYDONEQUEUE prefix of the queue each city controller hears back on
+	// once a worker has finished one of its jobs
+	CITYDONEQUEUE = "city_done_queue"
+	// TRAFFICDONEQUEUE prefix of the queue each traffic controller hears back
+	// on once a worker has finished one of its jobs
+	TRAFFICDONEQUEUE = "traffic_done_queue"
+)
+
+// DoneQueue the queue a controller hears back on when its jobs are finished
+func DoneQueue(prefix string, controller string) string {
+	return prefix + "." + controller
+}
+
+// Queue state of a declared queue
+type Queue struct {
+	Name      string `json:"name"`
+	Messages  int    `json:"messages"`
+	Consumers int    `json:"consumers"`
+}
+
+// Delivery a message received from a queue
+type Delivery struct {
+	Body []byte
+	ack  func(multiple bool) error
+}
+
+// Ack acknowledge the delivery so the broker can hand out the next message
+func (d Delivery) Ack(multiple bool) error {
+	if d.ack == nil {
+		return nil
+	}
+	return d.ack(multiple)
+}
+
+// Broker message broker used by controllers and workers. Queues are durable,
+// messages are persistent and each consumer gets one unacknowledged message
+// at a time.
+type Broker interface {
+	// QueueDeclare create the queue if needed and return its state
+	QueueDeclare(name string) (Queue, error)
+	// Publish send a JSON message to the queue
+	Publish(queue string, body []byte) error
+	// Consume register a consumer on the queue
+	Consume(queue string) (<-chan Delivery, error)
+	// QueueInspect return the current state of the queue
+	QueueInspect(name string) (Queue, error)
+	// QueuePurge drop all ready messages from the queue, returning how many were dropped
+	QueuePurge(name string) (int, error)
+	// QueueDelete remove the queue and its messages, returning how many were dropped
+	QueueDelete(name string) (int, error)
+	// Close release the connection to the broker
+	Close() error
+}
+
+// Open connect to the broker at url. "memory://" gives an in-process broker
+// that only the goroutines of this process can reach, anything else is dialed
+// as an AMQP url.
+func Open(url string) (Broker, error) {
+	if strings.HasPrefix(url, "memory://") {
+		return NewMemory(), nil
+	}
+	return NewRabbitMQ(url)
+}
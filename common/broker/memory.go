@@ -0,0 +1,179 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClosed returned when using a broker after Close
+var ErrClosed = errors.New("broker: closed")
+
+// Memory in-process broker built on channels, used to run the tick pipeline
+// without a RabbitMQ server. Every process gets its own private broker, so the
+// controllers and workers only talk to each other when they run in the same
+// process, e.g. in tests. Separate binaries need RabbitMQ.
+type Memory struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+	done   chan struct{}
+	closed bool
+}
+
+type memoryQueue struct {
+	name      string
+	messages  [][]byte
+	consumers int
+	deleted   bool
+	ready     *sync.Cond
+}
+
+// NewMemory create an empty in-process broker
+func NewMemory() *Memory {
+	return &Memory{queues: map[string]*memoryQueue{}, done: make(chan struct{})}
+}
+
+func (m *Memory) queue(name string) (*memoryQueue, error) {
+	if m.closed {
+		return nil, ErrClosed
+	}
+	q, ok := m.queues[name]
+	if !ok {
+		return nil, fmt.Errorf("broker: queue %q not declared", name)
+	}
+	return q, nil
+}
+
+// QueueDeclare create the queue if it does not exist yet
+func (m *Memory) QueueDeclare(name string) (Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return Queue{}, ErrClosed
+	}
+	q, ok := m.queues[name]
+	if !ok {
+		q = &memoryQueue{name: name, ready: sync.NewCond(&m.mu)}
+		m.queues[name] = q
+	}
+	return Queue{Name: q.name, Messages: len(q.messages), Consumers: q.consumers}, nil
+}
+
+// Publish append a message to the queue
+func (m *Memory) Publish(queue string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, err := m.queue(queue)
+	if err != nil {
+		return err
+	}
+	msg := make([]byte, len(body))
+	copy(msg, body)
+	q.messages = append(q.messages, msg)
+	q.ready.Signal()
+	return nil
+}
+
+// Consume register a consumer, each consumer holds one unacknowledged message at a time
+func (m *Memory) Consume(queue string) (<-chan Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, err := m.queue(queue)
+	if err != nil {
+		return nil, err
+	}
+	q.consumers++
+	out := make(chan Delivery)
+	go m.deliver(q, out)
+	return out, nil
+}
+
+func (m *Memory) deliver(q *memoryQueue, out chan Delivery) {
+	defer close(out)
+	for {
+		m.mu.Lock()
+		for len(q.messages) == 0 && !m.closed && !q.deleted {
+			q.ready.Wait()
+		}
+		if m.closed || q.deleted {
+			m.mu.Unlock()
+			return
+		}
+		body := q.messages[0]
+		q.messages = q.messages[1:]
+		m.mu.Unlock()
+
+		acked := make(chan struct{})
+		var once sync.Once
+		d := Delivery{Body: body, ack: func(multiple bool) error {
+			once.Do(func() { close(acked) })
+			return nil
+		}}
+		select {
+		case out <- d:
+		case <-m.done:
+			return
+		}
+		select {
+		case <-acked:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// QueueInspect return the number of ready messages and consumers
+func (m *Memory) QueueInspect(name string) (Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, err := m.queue(name)
+	if err != nil {
+		return Queue{}, err
+	}
+	return Queue{Name: q.name, Messages: len(q.messages), Consumers: q.consumers}, nil
+}
+
+// QueuePurge drop all ready messages
+func (m *Memory) QueuePurge(name string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, err := m.queue(name)
+	if err != nil {
+		return 0, err
+	}
+	purged := len(q.messages)
+	q.messages = nil
+	return purged, nil
+}
+
+// QueueDelete drop the queue and its messages, its consumers' delivery
+// channels are closed
+func (m *Memory) QueueDelete(name string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, err := m.queue(name)
+	if err != nil {
+		return 0, err
+	}
+	dropped := len(q.messages)
+	q.messages = nil
+	q.deleted = true
+	delete(m.queues, name)
+	q.ready.Broadcast()
+	return dropped, nil
+}
+
+// Close stop all consumers, closing their delivery channels
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	for _, q := range m.queues {
+		q.ready.Broadcast()
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package broker
+
+import (
+	"github.com/streadway/amqp"
+)
+
+// RabbitMQ broker backed by a RabbitMQ server
+type RabbitMQ struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// NewRabbitMQ dial the RabbitMQ server at url
+func NewRabbitMQ(url string) (*RabbitMQ, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	err = ch.Qos(
+		1,     // prefetch count
+		0,     // prefetch size
+		false, // global
+	)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, err
+	}
+	return &RabbitMQ{conn: conn, ch: ch}, nil
+}
+
+// QueueDeclare declare a durable queue
+func (r *RabbitMQ) QueueDeclare(name string) (Queue, error) {
+	q, err := r.ch.QueueDeclare(
+		name,  // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	return Queue{Name: q.Name, Messages: q.Messages, Consumers: q.Consumers}, err
+}
+
+// Publish publish a persistent JSON message to the queue
+func (r *RabbitMQ) Publish(queue string, body []byte) error {
+	return r.ch.Publish(
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Body:         body,
+		})
+}
+
+// Consume register a consumer with manual acks
+func (r *RabbitMQ) Consume(queue string) (<-chan Delivery, error) {
+	msgs, err := r.ch.Consume(
+		queue, // queue
+		"",    // consumer
+		false, // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Delivery)
+	go func() {
+		for d := range msgs {
+			out <- Delivery{Body: d.Body, ack: d.Ack}
+		}
+		close(out)
+	}()
+	return out, nil
+}
+
+// QueueInspect inspect the queue without declaring it
+func (r *RabbitMQ) QueueInspect(name string) (Queue, error) {
+	q, err := r.ch.QueueInspect(name)
+	return Queue{Name: q.Name, Messages: q.Messages, Consumers: q.Consumers}, err
+}
+
+// QueuePurge purge ready messages from the queue
+func (r *RabbitMQ) QueuePurge(name string) (int, error) {
+	return r.ch.QueuePurge(name, false)
+}
+
+// QueueDelete delete the queue even if it still has consumers or messages
+func (r *RabbitMQ) QueueDelete(name string) (int, error) {
+	return r.ch.QueueDelete(name, false, false, false)
+}
+
+// Close close the channel and the connection
+func (r *RabbitMQ) Close() error {
+	r.ch.Close()
+	return r.conn.Close()
+}
@@ -0,0 +1,168 @@
+package construction
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/economy"
+	"github.com/toasterlint/DAWS/common/education"
+	"github.com/toasterlint/DAWS/common/geometry"
+	"github.com/toasterlint/DAWS/common/healthcare"
+	"github.com/toasterlint/DAWS/common/jobs"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/parcels"
+	"github.com/toasterlint/DAWS/common/police"
+	"github.com/toasterlint/DAWS/common/random"
+)
+
+const (
+	// HOUSINGPRESSURE share of homes filled before more are built
+	HOUSINGPRESSURE = 0.8
+	// APARTMENTPOPULATION residents a city needs before apartments are built
+	// instead of houses
+	APARTMENTPOPULATION = 200
+	// RESIDENTSPERBED residents a city has for each hospital bed it wants
+	RESIDENTSPERBED = 50
+	// RESIDENTSPERSTATION residents a city has for each police station it wants
+	RESIDENTSPERSTATION = 1000
+)
+
+// Project what to build and how big
+type Project struct {
+	Type         commonModels.BuildingType
+	Name         string
+	Floors       int
+	MaxOccupancy int
+	// Duration time from breaking ground until the building can be used
+	Duration time.Duration
+}
+
+// employers mix of workplaces the market builds when there aren't enough jobs
+var employers = []commonModels.BuildingType{
+	commonModels.Office, commonModels.Office, commonModels.Office,
+	commonModels.Retail, commonModels.Retail,
+	commonModels.Warehouse, commonModels.Warehouse,
+	commonModels.Entertainment,
+}
+
+// Plan the size and construction time of a new building of a type
+func Plan(rng *rand.Rand, buildingType commonModels.BuildingType) Project {
+	days := func(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
+	switch buildingType {
+	case commonModels.House:
+		return Project{Type: buildingType, Name: "House", Floors: 1 + rng.Intn(2), MaxOccupancy: 6 + rng.Intn(5), Duration: days(60)}
+	case commonModels.Apartment:
+		floors := 3 + rng.Intn(6)
+		return Project{Type: buildingType, Name: "Apartments", Floors: floors, MaxOccupancy: floors * 12, Duration: days(180)}
+	case commonModels.School:
+		return Project{Type: buildingType, Name: "School", Floors: 2, MaxOccupancy: 300, Duration: days(270)}
+	case commonModels.Hospital:
+		return Project{Type: buildingType, Name: "Hospital", Floors: 3 + rng.Intn(3), MaxOccupancy: 200, Duration: days(365)}
+	case commonModels.Police:
+		return Project{Type: buildingType, Name: "Police Station", Floors: 2, MaxOccupancy: 40, Duration: days(180)}
+	case commonModels.Office:
+		floors := 2 + rng.Intn(5)
+		return Project{Type: buildingType, Name: "Office", Floors: floors, MaxOccupancy: floors * 25, Duration: days(240)}
+	case commonModels.Retail:
+		return Project{Type: buildingType, Name: "Shop", Floors: 1 + rng.Intn(2), MaxOccupancy: 60, Duration: days(90)}
+	case commonModels.Warehouse:
+		return Project{Type: buildingType, Name: "Warehouse", Floors: 1, MaxOccupancy: 30, Duration: days(120)}
+	case commonModels.Entertainment:
+		return Project{Type: buildingType, Name: "Theater", Floors: 1 + rng.Intn(2), MaxOccupancy: 150, Duration: days(150)}
+	}
+	return Project{Type: buildingType, Name: "Building", Floors: 1, MaxOccupancy: 10, Duration: days(90)}
+}
+
+// Place a new building on a free lot of the city's land, ready to use at
+// ready. False if there's no room left.
+func Place(rng *rand.Rand, land *parcels.Land, city commonModels.City, project Project, ready time.Time) (commonModels.Building, bool) {
+	lot, ok := land.Place(rng, geometry.FOOTPRINT, geometry.FOOTPRINT)
+	if !ok {
+		return commonModels.Building{}, false
+	}
+	newBuilding := commonModels.Building{}
+	newBuilding.ID = random.ObjectID(rng)
+	newBuilding.BuildDate = ready
+	newBuilding.Floors = project.Floors
+	newBuilding.MaxOccupancy = project.MaxOccupancy
+	newBuilding.Name = project.Name
+	newBuilding.Type = project.Type
+	newBuilding.TopLeft = lot.Min
+	newBuilding.BottomRight = lot.Max
+	newBuilding.CityID = city.ID
+	return newBuilding, true
+}
+
+// Homes houses and apartments in the list that are open at t
+func Homes(buildings []commonModels.Building, t time.Time) []commonModels.Building {
+	homes := []commonModels.Building{}
+	for _, b := range buildings {
+		if economy.Rent(b) > 0 && !b.BuildDate.After(t) {
+			homes = append(homes, b)
+		}
+	}
+	return homes
+}
+
+// Demand what a city needs built at t given its buildings, counting those
+// still under construction, and its residents. At most one of each kind of
+// building is asked for at a time.
+func Demand(rng *rand.Rand, buildings []commonModels.Building, residents []commonModels.Person, t time.Time) []commonModels.BuildingType {
+	// everything planned, built or not
+	future := t.AddDate(100, 0, 0)
+	housing, jobSlots, beds, schoolPlaces := 0, 0, 0, 0
+	for _, b := range Homes(buildings, future) {
+		housing += b.MaxOccupancy
+	}
+	for _, b := range buildings {
+		jobSlots += jobs.Slots(b)
+	}
+	for _, b := range healthcare.Hospitals(buildings, future) {
+		beds += healthcare.Beds(b)
+	}
+	for _, b := range education.Schools(buildings, future) {
+		schoolPlaces += b.MaxOccupancy
+	}
+	stations := len(police.Stations(buildings, future))
+
+	workers, students := 0, 0
+	for _, p := range residents {
+		if jobs.Workforce(p, t) {
+			workers++
+		}
+		age := demographics.Age(p, t)
+		if age >= education.PRIMARYAGE && age < education.COLLEGEAGE {
+			students++
+		}
+	}
+	population := len(residents)
+
+	demand := []commonModels.BuildingType{}
+	if float64(population) > HOUSINGPRESSURE*float64(housing) {
+		if population >= APARTMENTPOPULATION {
+			demand = append(demand, commonModels.Apartment)
+		} else {
+			demand = append(demand, commonModels.House)
+		}
+	}
+	if workers > jobSlots {
+		demand = append(demand, employers[rng.Intn(len(employers))])
+	}
+	if students > schoolPlaces {
+		demand = append(demand, commonModels.School)
+	}
+	if population > beds*RESIDENTSPERBED {
+		demand = append(demand, commonModels.Hospital)
+	}
+	if population > stations*RESIDENTSPERSTATION {
+		demand = append(demand, commonModels.Police)
+	}
+	return demand
+}
+
+// Name a name for the nth building of a project in a city
+func Name(project Project, n int) string {
+	return project.Name + " " + strconv.Itoa(n)
+}
@@ -0,0 +1,109 @@
+package crime
+
+import (
+	. "image"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SECONDSPERYEAR used to turn the yearly crime rates into chances per tick
+const SECONDSPERYEAR = demographics.DAYSPERYEAR * 24 * 60 * 60
+
+// Expected crimes expected in one tick for a population at a yearly rate per person
+func Expected(rate float32, population int) float64 {
+	return float64(rate) * float64(population) / SECONDSPERYEAR
+}
+
+// Poisson number of events in an interval with lambda expected events
+func Poisson(rng *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	limit := math.Exp(-lambda)
+	k := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		k++
+	}
+	return k
+}
+
+// Sample roll this tick's violent crimes and murders against the city's
+// population. The types are returned in the order they should be committed.
+func Sample(rng *rand.Rand, settings commonModels.Settings, population int) []commonModels.CrimeType {
+	crimes := []commonModels.CrimeType{}
+	for i := Poisson(rng, Expected(settings.ViolentCrimeRate, population)); i > 0; i-- {
+		crimes = append(crimes, commonModels.ViolentCrime)
+	}
+	for i := Poisson(rng, Expected(settings.MurderRate, population)); i > 0; i-- {
+		crimes = append(crimes, commonModels.Homicide)
+	}
+	return crimes
+}
+
+// Commit pick a victim and perpetrator among the residents and carry out the
+// crime, returning the crime and the index of the victim. deterrence gives the
+// chance a crime at a location still goes ahead, nil if nothing deters it. ok
+// is false when there is nobody left to be a victim or the crime was deterred.
+func Commit(rng *rand.Rand, crimeType commonModels.CrimeType, cityID bson.ObjectId, residents []commonModels.Person, t time.Time, deterrence func(Point) float64) (crime commonModels.Crime, victim int, ok bool) {
+	living := []int{}
+	for i := range residents {
+		if demographics.Alive(residents[i]) {
+			living = append(living, i)
+		}
+	}
+	if len(living) == 0 {
+		return crime, 0, false
+	}
+	victim = living[rng.Intn(len(living))]
+	v := &residents[victim]
+	if deterrence != nil && rng.Float64() >= deterrence(v.CurrentXY) {
+		return crime, victim, false
+	}
+
+	crime.ID = random.ObjectID(rng)
+	crime.Type = crimeType
+	crime.Time = t
+	crime.CityID = cityID
+	crime.VictimID = v.ID
+	crime.Location = v.CurrentXY
+	if !v.Traveling {
+		crime.BuildingID = v.CurrentBuilding
+	}
+	if len(living) > 1 {
+		perpetrator := living[rng.Intn(len(living)-1)]
+		if perpetrator == victim {
+			perpetrator = living[len(living)-1]
+		}
+		crime.PerpetratorID = residents[perpetrator].ID
+	}
+
+	if Harm(rng, v, crimeType, t) {
+		crime.Type = commonModels.Homicide
+	}
+	return crime, victim, true
+}
+
+// Harm the effect of a crime on its victim, returning true if they were killed
+func Harm(rng *rand.Rand, victim *commonModels.Person, crimeType commonModels.CrimeType, t time.Time) bool {
+	if crimeType == commonModels.Homicide {
+		demographics.Die(victim, t, commonModels.Murder)
+		return true
+	}
+	victim.Happiness -= 10 + rng.Intn(21)
+	if victim.Happiness < 0 {
+		victim.Happiness = 0
+	}
+	victim.Health -= 10 + rng.Intn(31)
+	if victim.Health <= 0 {
+		victim.Health = 0
+		demographics.Die(victim, t, commonModels.Murder)
+		return true
+	}
+	return false
+}
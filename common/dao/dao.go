@@ -1,6 +1,7 @@
 package dao
 
 import (
+	. "image"
 	"time"
 
 	commonModels "github.com/toasterlint/DAWS/common/models"
@@ -9,7 +10,8 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-// DAO Data Access Object
+// DAO Data Access Object backed by MongoDB. Queries the tick depends on sort
+// by _id so every backend returns them in the same order.
 type DAO struct {
 	Server   string
 	Database string
@@ -30,6 +32,20 @@ const (
 	COLLECTIONCITY = "city"
 	// COLLECTIONBUILDING Building collection to use in DB
 	COLLECTIONBUILDING = "building"
+	// COLLECTIONHIGHWAY Highway collection to use in DB
+	COLLECTIONHIGHWAY = "highway"
+	// COLLECTIONCRIME Crime collection to use in DB
+	COLLECTIONCRIME = "crime"
+	// COLLECTIONACCIDENT Accident collection to use in DB
+	COLLECTIONACCIDENT = "accident"
+	// COLLECTIONDISEASESTATS Disease stats collection to use in DB
+	COLLECTIONDISEASESTATS = "diseasestats"
+	// COLLECTIONCITYSTATS City stats collection to use in DB
+	COLLECTIONCITYSTATS = "citystats"
+	// COLLECTIONTERRAIN Terrain tile collection to use in DB
+	COLLECTIONTERRAIN = "terrain"
+	// COLLECTIONSETTINGS Settings collection to use in DB
+	COLLECTIONSETTINGS = "settings"
 )
 
 // Connect to DB
@@ -57,6 +73,19 @@ func (m *DAO) UpdateCity(city commonModels.City) error {
 	return err
 }
 
+// AdjustCityBalance add amount to a city's balance, negative to take it away.
+// Only the balance is written, so it's safe from many workers at once.
+func (m *DAO) AdjustCityBalance(cityid Mongoid, amount int) error {
+	return db.C(COLLECTIONCITY).UpdateId(cityid.ID, bson.M{"$inc": bson.M{"balance": amount}})
+}
+
+// GetCity get a city by ID
+func (m *DAO) GetCity(id Mongoid) (commonModels.City, error) {
+	var city commonModels.City
+	err := db.C(COLLECTIONCITY).FindId(id.ID).One(&city)
+	return city, err
+}
+
 // GetCitiesCount get number of cities in the world
 func (m *DAO) GetCitiesCount() (int, error) {
 	citiesCount, err := db.C(COLLECTIONCITY).Find(bson.M{}).Count()
@@ -65,10 +94,17 @@ func (m *DAO) GetCitiesCount() (int, error) {
 
 func (m *DAO) GetAllCityIDs() ([]Mongoid, error) {
 	var cityids []Mongoid
-	err := db.C(COLLECTIONCITY).Find(bson.M{}).Select(bson.M{"_id": 1}).All(&cityids)
+	err := db.C(COLLECTIONCITY).Find(bson.M{}).Select(bson.M{"_id": 1}).Sort("_id").All(&cityids)
 	return cityids, err
 }
 
+// GetAllCities get every city
+func (m *DAO) GetAllCities() ([]commonModels.City, error) {
+	var cities []commonModels.City
+	err := db.C(COLLECTIONCITY).Find(bson.M{}).Sort("_id").All(&cities)
+	return cities, err
+}
+
 // CreateBuilding Creates a city in DB
 func (m *DAO) CreateBuilding(building commonModels.Building) error {
 	err := db.C(COLLECTIONBUILDING).Insert(&building)
@@ -81,6 +117,13 @@ func (m *DAO) UpdateBuilding(building commonModels.Building) error {
 	return err
 }
 
+// GetBuilding get a building by ID
+func (m *DAO) GetBuilding(id Mongoid) (commonModels.Building, error) {
+	var building commonModels.Building
+	err := db.C(COLLECTIONBUILDING).FindId(id.ID).One(&building)
+	return building, err
+}
+
 // GetBuildingsCount get number of buildings in the world
 func (m *DAO) GetBuildingsCount() (int, error) {
 	buildingsCount, err := db.C(COLLECTIONBUILDING).Find(bson.M{}).Count()
@@ -89,25 +132,277 @@ func (m *DAO) GetBuildingsCount() (int, error) {
 
 func (m *DAO) GetAllBuildingIDs(cityid Mongoid) ([]Mongoid, error) {
 	var buildingids []Mongoid
-	err := db.C(COLLECTIONBUILDING).Find(bson.M{"cityid": cityid.ID}).Select(bson.M{"_id": 1}).All(&buildingids)
+	err := db.C(COLLECTIONBUILDING).Find(bson.M{"cityid": cityid.ID}).Select(bson.M{"_id": 1}).Sort("_id").All(&buildingids)
 	return buildingids, err
 }
 
+// GetBuildings get every building in a city
+func (m *DAO) GetBuildings(cityid Mongoid) ([]commonModels.Building, error) {
+	var buildings []commonModels.Building
+	err := db.C(COLLECTIONBUILDING).Find(bson.M{"cityid": cityid.ID}).Sort("_id").All(&buildings)
+	return buildings, err
+}
+
 // CreatePerson Creates a city in DB
 func (m *DAO) CreatePerson(person commonModels.Person) error {
 	err := db.C(COLLECTIONPEOPLE).Insert(&person)
 	return err
 }
 
-// GetPeopleCount get number of people in the world
+// UpdatePerson updates a person
+func (m *DAO) UpdatePerson(person commonModels.Person) error {
+	err := db.C(COLLECTIONPEOPLE).UpdateId(person.ID, &person)
+	return err
+}
+
+// DispatchOfficer save an officer heading out to a crime, only if they are
+// still alive and free inside the station. Only the fields a dispatch changes
+// are written. Returns mgo.ErrNotFound if the officer is no longer available.
+func (m *DAO) DispatchOfficer(officer commonModels.Person, stationid Mongoid) error {
+	set := bson.M{
+		"responding":    officer.Responding,
+		"traveling":     officer.Traveling,
+		"newtobuilding": officer.NewToBuilding,
+		"destination":   officer.Destination,
+		"route":         officer.Route,
+	}
+	unset := bson.M{}
+	if officer.DestinationID.Valid() {
+		set["destinationid"] = officer.DestinationID
+	} else {
+		unset["destinationid"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return db.C(COLLECTIONPEOPLE).Update(bson.M{
+		"_id":             officer.ID,
+		"currentbuilding": stationid.ID,
+		"traveling":       false,
+		"responding":      nil,
+		"deathdate":       time.Time{},
+	}, update)
+}
+
+// HarmPerson save what a crime did to its victim, only if they are still
+// alive and not traveling. Only health, happiness and death are written.
+// Returns mgo.ErrNotFound if the victim has died or left since.
+func (m *DAO) HarmPerson(victim commonModels.Person) error {
+	return db.C(COLLECTIONPEOPLE).Update(bson.M{
+		"_id":       victim.ID,
+		"traveling": false,
+		"deathdate": time.Time{},
+	}, bson.M{"$set": bson.M{
+		"health":       victim.Health,
+		"happiness":    victim.Happiness,
+		"deathdate":    victim.DeathDate,
+		"causeofdeath": victim.CauseOfDeath,
+	}})
+}
+
+// GetPerson get a person by ID
+func (m *DAO) GetPerson(id Mongoid) (commonModels.Person, error) {
+	var person commonModels.Person
+	err := db.C(COLLECTIONPEOPLE).FindId(id.ID).One(&person)
+	return person, err
+}
+
+// GetPeopleCount get number of living people in the world
 func (m *DAO) GetPeopleCount() (int, error) {
-	peopleCount, err := db.C(COLLECTIONPEOPLE).Find(bson.M{}).Count()
+	peopleCount, err := db.C(COLLECTIONPEOPLE).Find(bson.M{"deathdate": time.Time{}}).Count()
 	return peopleCount, err
 }
 
-// GetAllTravelers return peopel who are traveling
+// GetAllTravelers return living people who are traveling
 func (m *DAO) GetAllTravelers() ([]Mongoid, error) {
 	var peopleids []Mongoid
-	err := db.C(COLLECTIONPEOPLE).Find(bson.M{"traveling": true}).Select(bson.M{"_id": 1}).All(&peopleids)
+	err := db.C(COLLECTIONPEOPLE).Find(bson.M{"traveling": true, "deathdate": time.Time{}}).Select(bson.M{"_id": 1}).Sort("_id").All(&peopleids)
 	return peopleids, err
 }
+
+// GetTravelerLocations return where every living traveler is
+func (m *DAO) GetTravelerLocations() ([]Point, error) {
+	var people []commonModels.Person
+	err := db.C(COLLECTIONPEOPLE).Find(bson.M{"traveling": true, "deathdate": time.Time{}}).Select(bson.M{"currentxy": 1}).Sort("_id").All(&people)
+	locations := []Point{}
+	for _, p := range people {
+		locations = append(locations, p.CurrentXY)
+	}
+	return locations, err
+}
+
+// GetPeopleInBuilding return living people inside a building who are not traveling
+func (m *DAO) GetPeopleInBuilding(buildingid Mongoid) ([]commonModels.Person, error) {
+	var people []commonModels.Person
+	err := db.C(COLLECTIONPEOPLE).Find(bson.M{"currentbuilding": buildingid.ID, "traveling": false, "deathdate": time.Time{}}).Sort("_id").All(&people)
+	return people, err
+}
+
+// CreateHighway Creates a highway between two cities in DB
+func (m *DAO) CreateHighway(highway commonModels.HighwayRoute) error {
+	err := db.C(COLLECTIONHIGHWAY).Insert(&highway)
+	return err
+}
+
+// GetAllHighways get every highway
+func (m *DAO) GetAllHighways() ([]commonModels.HighwayRoute, error) {
+	var highways []commonModels.HighwayRoute
+	err := db.C(COLLECTIONHIGHWAY).Find(bson.M{}).Sort("_id").All(&highways)
+	return highways, err
+}
+
+// RecordDiseaseStat add the counts to the disease's stats for the day
+func (m *DAO) RecordDiseaseStat(stat commonModels.DiseaseStat) error {
+	_, err := db.C(COLLECTIONDISEASESTATS).Upsert(
+		bson.M{"diseaseid": stat.DiseaseID, "day": stat.Day},
+		bson.M{"$inc": bson.M{"newcases": stat.NewCases, "recoveries": stat.Recoveries, "deaths": stat.Deaths}})
+	return err
+}
+
+// GetDiseaseStats get the daily stats of a disease, oldest first
+func (m *DAO) GetDiseaseStats(diseaseid Mongoid) ([]commonModels.DiseaseStat, error) {
+	var stats []commonModels.DiseaseStat
+	err := db.C(COLLECTIONDISEASESTATS).Find(bson.M{"diseaseid": diseaseid.ID}).Sort("day").All(&stats)
+	return stats, err
+}
+
+// GetTerrainTile get the terrain tile at x, y
+func (m *DAO) GetTerrainTile(x int, y int) (commonModels.TerrainTile, error) {
+	var tile commonModels.TerrainTile
+	err := db.C(COLLECTIONTERRAIN).Find(bson.M{"x": x, "y": y}).One(&tile)
+	return tile, err
+}
+
+// SaveTerrainTile save a terrain tile, replacing any already at its x, y
+func (m *DAO) SaveTerrainTile(tile commonModels.TerrainTile) error {
+	tile.ID = ""
+	_, err := db.C(COLLECTIONTERRAIN).Upsert(bson.M{"x": tile.X, "y": tile.Y}, bson.M{"$set": tile})
+	return err
+}
+
+// SaveCityStat save the city's stats for the day, replacing any already saved
+func (m *DAO) SaveCityStat(stat commonModels.CityStat) error {
+	stat.ID = ""
+	_, err := db.C(COLLECTIONCITYSTATS).Upsert(bson.M{"cityid": stat.CityID, "day": stat.Day}, bson.M{"$set": stat})
+	return err
+}
+
+// GetCityStats get the daily stats of a city, oldest first
+func (m *DAO) GetCityStats(cityid Mongoid) ([]commonModels.CityStat, error) {
+	var stats []commonModels.CityStat
+	err := db.C(COLLECTIONCITYSTATS).Find(bson.M{"cityid": cityid.ID}).Sort("day").All(&stats)
+	return stats, err
+}
+
+// GetResidentsCount get number of living people whose home is in the city
+func (m *DAO) GetResidentsCount(cityid Mongoid) (int, error) {
+	homes, err := m.buildingIDs(cityid)
+	if err != nil {
+		return 0, err
+	}
+	return db.C(COLLECTIONPEOPLE).Find(bson.M{"homebuilding": bson.M{"$in": homes}, "deathdate": time.Time{}}).Count()
+}
+
+// GetPatientsCount get number of living people admitted to a hospital
+func (m *DAO) GetPatientsCount(hospitalid Mongoid) (int, error) {
+	return db.C(COLLECTIONPEOPLE).Find(bson.M{"hospital": hospitalid.ID, "deathdate": time.Time{}}).Count()
+}
+
+// GetEmployeesCount get number of living people who work in a building
+func (m *DAO) GetEmployeesCount(buildingid Mongoid) (int, error) {
+	return db.C(COLLECTIONPEOPLE).Find(bson.M{"workbuilding": buildingid.ID, "deathdate": time.Time{}}).Count()
+}
+
+// GetTenantsCount get number of living people whose home is a building
+func (m *DAO) GetTenantsCount(buildingid Mongoid) (int, error) {
+	return db.C(COLLECTIONPEOPLE).Find(bson.M{"homebuilding": buildingid.ID, "deathdate": time.Time{}}).Count()
+}
+
+// GetResidents get living people whose home is in the city
+func (m *DAO) GetResidents(cityid Mongoid) ([]commonModels.Person, error) {
+	var people []commonModels.Person
+	homes, err := m.buildingIDs(cityid)
+	if err != nil {
+		return people, err
+	}
+	err = db.C(COLLECTIONPEOPLE).Find(bson.M{"homebuilding": bson.M{"$in": homes}, "deathdate": time.Time{}}).Sort("_id").All(&people)
+	return people, err
+}
+
+// GetChildren get the IDs of everyone whose mother or father is the parent
+func (m *DAO) GetChildren(parentid Mongoid) ([]Mongoid, error) {
+	var children []Mongoid
+	err := db.C(COLLECTIONPEOPLE).Find(bson.M{"$or": []bson.M{{"mother": parentid.ID}, {"father": parentid.ID}}}).Select(bson.M{"_id": 1}).Sort("_id").All(&children)
+	return children, err
+}
+
+func (m *DAO) buildingIDs(cityid Mongoid) ([]bson.ObjectId, error) {
+	buildingids, err := m.GetAllBuildingIDs(cityid)
+	ids := []bson.ObjectId{}
+	for _, b := range buildingids {
+		ids = append(ids, b.ID)
+	}
+	return ids, err
+}
+
+// CreateCrime Creates a crime in DB
+func (m *DAO) CreateCrime(crime commonModels.Crime) error {
+	err := db.C(COLLECTIONCRIME).Insert(&crime)
+	return err
+}
+
+// UpdateCrime updates a crime
+func (m *DAO) UpdateCrime(crime commonModels.Crime) error {
+	err := db.C(COLLECTIONCRIME).UpdateId(crime.ID, &crime)
+	return err
+}
+
+// GetCrime get a crime by ID
+func (m *DAO) GetCrime(id Mongoid) (commonModels.Crime, error) {
+	var crime commonModels.Crime
+	err := db.C(COLLECTIONCRIME).FindId(id.ID).One(&crime)
+	return crime, err
+}
+
+// GetCrimes get crimes in a city since a time, oldest first
+func (m *DAO) GetCrimes(cityid Mongoid, since time.Time) ([]commonModels.Crime, error) {
+	var crimes []commonModels.Crime
+	err := db.C(COLLECTIONCRIME).Find(bson.M{"cityid": cityid.ID, "time": bson.M{"$gte": since}}).Sort("time").All(&crimes)
+	return crimes, err
+}
+
+// CreateAccident Creates an accident in DB
+func (m *DAO) CreateAccident(accident commonModels.CarAccident) error {
+	err := db.C(COLLECTIONACCIDENT).Insert(&accident)
+	return err
+}
+
+// GetAccidents get accidents since a time, oldest first
+func (m *DAO) GetAccidents(since time.Time) ([]commonModels.CarAccident, error) {
+	var accidents []commonModels.CarAccident
+	err := db.C(COLLECTIONACCIDENT).Find(bson.M{"time": bson.M{"$gte": since}}).Sort("time").All(&accidents)
+	return accidents, err
+}
+
+// SaveSettings save settings to DB
+func (m *DAO) SaveSettings(settings commonModels.Settings) error {
+	err := db.C(COLLECTIONSETTINGS).UpdateId(settings.ID, &settings)
+	return err
+}
+
+// LoadSettings load settings from DB
+func (m *DAO) LoadSettings() (commonModels.Settings, error) {
+	var settings []commonModels.Settings
+	err := db.C(COLLECTIONSETTINGS).Find(bson.M{}).All(&settings)
+	if len(settings) > 0 {
+		return settings[0], err
+	}
+	return commonModels.Settings{}, err
+}
+
+// InsertSettings create settings in DB
+func (m *DAO) InsertSettings(settings commonModels.Settings) error {
+	err := db.C(COLLECTIONSETTINGS).Insert(&settings)
+	return err
+}
@@ -0,0 +1,651 @@
+package dao
+
+import (
+	. "image"
+	"sort"
+	"sync"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MemoryDAO in-memory WorldStore for tests and small worlds that don't need
+// MongoDB. Every process gets its own empty store, so it only suits a pipeline
+// running in one process. Lists come back sorted by _id like the Mongo DAO
+// returns them, so a seed plays out the same on either backend.
+type MemoryDAO struct {
+	mu        sync.RWMutex
+	cities    []commonModels.City
+	buildings []commonModels.Building
+	people    []commonModels.Person
+	highways  []commonModels.HighwayRoute
+	stats     []commonModels.DiseaseStat
+	cityStats []commonModels.CityStat
+	terrain   map[Point]commonModels.TerrainTile
+	crimes    []commonModels.Crime
+	accidents []commonModels.CarAccident
+	settings  []commonModels.Settings
+	index     map[string]map[bson.ObjectId]int
+}
+
+// NewMemoryDAO create an empty in-memory store
+func NewMemoryDAO() *MemoryDAO {
+	m := &MemoryDAO{}
+	m.Connect()
+	return m
+}
+
+// Connect prepare the store, there is nothing to connect to
+func (m *MemoryDAO) Connect() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.index == nil {
+		m.index = map[string]map[bson.ObjectId]int{}
+		for _, collection := range []string{COLLECTIONCITY, COLLECTIONBUILDING, COLLECTIONPEOPLE, COLLECTIONHIGHWAY, COLLECTIONCRIME, COLLECTIONACCIDENT} {
+			m.index[collection] = map[bson.ObjectId]int{}
+		}
+	}
+	if m.terrain == nil {
+		m.terrain = map[Point]commonModels.TerrainTile{}
+	}
+}
+
+// insert index a new record at n in its collection. Like MongoDB an empty ID
+// is given a new one, and a duplicate ID in the same collection is an error.
+func (m *MemoryDAO) insert(collection string, id *bson.ObjectId, n int) error {
+	if *id == "" {
+		*id = bson.NewObjectId()
+	}
+	if _, ok := m.index[collection][*id]; ok {
+		return &mgo.LastError{Code: 11000, Err: "duplicate key: " + id.Hex()}
+	}
+	m.index[collection][*id] = n
+	return nil
+}
+
+// CreateCity Creates a city in memory
+func (m *MemoryDAO) CreateCity(city commonModels.City) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONCITY, &city.ID, len(m.cities)); err != nil {
+		return err
+	}
+	m.cities = append(m.cities, city)
+	return nil
+}
+
+// UpdateCity updates a city
+func (m *MemoryDAO) UpdateCity(city commonModels.City) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONCITY][city.ID]
+	if !ok || i >= len(m.cities) || m.cities[i].ID != city.ID {
+		return mgo.ErrNotFound
+	}
+	m.cities[i] = city
+	return nil
+}
+
+// AdjustCityBalance add amount to a city's balance, negative to take it away
+func (m *MemoryDAO) AdjustCityBalance(cityid Mongoid, amount int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONCITY][cityid.ID]
+	if !ok || i >= len(m.cities) || m.cities[i].ID != cityid.ID {
+		return mgo.ErrNotFound
+	}
+	m.cities[i].Balance += amount
+	return nil
+}
+
+// GetCity get a city by ID
+func (m *MemoryDAO) GetCity(id Mongoid) (commonModels.City, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.index[COLLECTIONCITY][id.ID]
+	if !ok || i >= len(m.cities) || m.cities[i].ID != id.ID {
+		return commonModels.City{}, mgo.ErrNotFound
+	}
+	return m.cities[i], nil
+}
+
+// GetCitiesCount get number of cities in the world
+func (m *MemoryDAO) GetCitiesCount() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.cities), nil
+}
+
+// GetAllCityIDs get the IDs of every city
+func (m *MemoryDAO) GetAllCityIDs() ([]Mongoid, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cityids := []Mongoid{}
+	for i := range m.cities {
+		cityids = append(cityids, Mongoid{ID: m.cities[i].ID})
+	}
+	sort.Slice(cityids, func(i, j int) bool { return cityids[i].ID < cityids[j].ID })
+	return cityids, nil
+}
+
+// GetAllCities get every city
+func (m *MemoryDAO) GetAllCities() ([]commonModels.City, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cities := append([]commonModels.City{}, m.cities...)
+	sort.Slice(cities, func(i, j int) bool { return cities[i].ID < cities[j].ID })
+	return cities, nil
+}
+
+// CreateBuilding Creates a building in memory
+func (m *MemoryDAO) CreateBuilding(building commonModels.Building) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONBUILDING, &building.ID, len(m.buildings)); err != nil {
+		return err
+	}
+	m.buildings = append(m.buildings, building)
+	return nil
+}
+
+// UpdateBuilding updates a building
+func (m *MemoryDAO) UpdateBuilding(building commonModels.Building) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONBUILDING][building.ID]
+	if !ok || i >= len(m.buildings) || m.buildings[i].ID != building.ID {
+		return mgo.ErrNotFound
+	}
+	m.buildings[i] = building
+	return nil
+}
+
+// GetBuilding get a building by ID
+func (m *MemoryDAO) GetBuilding(id Mongoid) (commonModels.Building, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.index[COLLECTIONBUILDING][id.ID]
+	if !ok || i >= len(m.buildings) || m.buildings[i].ID != id.ID {
+		return commonModels.Building{}, mgo.ErrNotFound
+	}
+	return m.buildings[i], nil
+}
+
+// GetBuildingsCount get number of buildings in the world
+func (m *MemoryDAO) GetBuildingsCount() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.buildings), nil
+}
+
+// GetAllBuildingIDs get the IDs of every building in a city
+func (m *MemoryDAO) GetAllBuildingIDs(cityid Mongoid) ([]Mongoid, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	buildingids := []Mongoid{}
+	for i := range m.buildings {
+		if m.buildings[i].CityID == cityid.ID {
+			buildingids = append(buildingids, Mongoid{ID: m.buildings[i].ID})
+		}
+	}
+	sort.Slice(buildingids, func(i, j int) bool { return buildingids[i].ID < buildingids[j].ID })
+	return buildingids, nil
+}
+
+// GetBuildings get every building in a city
+func (m *MemoryDAO) GetBuildings(cityid Mongoid) ([]commonModels.Building, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	buildings := []commonModels.Building{}
+	for i := range m.buildings {
+		if m.buildings[i].CityID == cityid.ID {
+			buildings = append(buildings, m.buildings[i])
+		}
+	}
+	sort.Slice(buildings, func(i, j int) bool { return buildings[i].ID < buildings[j].ID })
+	return buildings, nil
+}
+
+// CreatePerson Creates a person in memory
+func (m *MemoryDAO) CreatePerson(person commonModels.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONPEOPLE, &person.ID, len(m.people)); err != nil {
+		return err
+	}
+	m.people = append(m.people, copyPerson(person))
+	return nil
+}
+
+// UpdatePerson updates a person
+func (m *MemoryDAO) UpdatePerson(person commonModels.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONPEOPLE][person.ID]
+	if !ok || i >= len(m.people) || m.people[i].ID != person.ID {
+		return mgo.ErrNotFound
+	}
+	m.people[i] = copyPerson(person)
+	return nil
+}
+
+// DispatchOfficer save an officer heading out to a crime, only if they are
+// still alive and free inside the station. Returns mgo.ErrNotFound if the
+// officer is no longer available.
+func (m *MemoryDAO) DispatchOfficer(officer commonModels.Person, stationid Mongoid) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONPEOPLE][officer.ID]
+	if !ok || i >= len(m.people) || m.people[i].ID != officer.ID {
+		return mgo.ErrNotFound
+	}
+	stored := &m.people[i]
+	if stored.CurrentBuilding != stationid.ID || stored.Traveling || stored.Responding.Valid() || !stored.DeathDate.IsZero() {
+		return mgo.ErrNotFound
+	}
+	stored.Responding = officer.Responding
+	stored.Traveling = officer.Traveling
+	stored.NewToBuilding = officer.NewToBuilding
+	stored.Destination = officer.Destination
+	stored.DestinationID = officer.DestinationID
+	stored.Route = append([]commonModels.Waypoint(nil), officer.Route...)
+	return nil
+}
+
+// HarmPerson save what a crime did to its victim, only if they are still
+// alive and not traveling. Returns mgo.ErrNotFound if the victim has died or
+// left since.
+func (m *MemoryDAO) HarmPerson(victim commonModels.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONPEOPLE][victim.ID]
+	if !ok || i >= len(m.people) || m.people[i].ID != victim.ID {
+		return mgo.ErrNotFound
+	}
+	stored := &m.people[i]
+	if stored.Traveling || !stored.DeathDate.IsZero() {
+		return mgo.ErrNotFound
+	}
+	stored.Health = victim.Health
+	stored.Happiness = victim.Happiness
+	stored.DeathDate = victim.DeathDate
+	stored.CauseOfDeath = victim.CauseOfDeath
+	return nil
+}
+
+// GetPerson get a person by ID
+func (m *MemoryDAO) GetPerson(id Mongoid) (commonModels.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.index[COLLECTIONPEOPLE][id.ID]
+	if !ok || i >= len(m.people) || m.people[i].ID != id.ID {
+		return commonModels.Person{}, mgo.ErrNotFound
+	}
+	return copyPerson(m.people[i]), nil
+}
+
+// GetPeopleCount get number of living people in the world
+func (m *MemoryDAO) GetPeopleCount() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for i := range m.people {
+		if m.people[i].DeathDate.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetAllTravelers return living people who are traveling
+func (m *MemoryDAO) GetAllTravelers() ([]Mongoid, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	peopleids := []Mongoid{}
+	for i := range m.people {
+		if m.people[i].Traveling && m.people[i].DeathDate.IsZero() {
+			peopleids = append(peopleids, Mongoid{ID: m.people[i].ID})
+		}
+	}
+	sort.Slice(peopleids, func(i, j int) bool { return peopleids[i].ID < peopleids[j].ID })
+	return peopleids, nil
+}
+
+// GetTravelerLocations return where every living traveler is
+func (m *MemoryDAO) GetTravelerLocations() ([]Point, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	travelers := []commonModels.Person{}
+	for i := range m.people {
+		if m.people[i].Traveling && m.people[i].DeathDate.IsZero() {
+			travelers = append(travelers, m.people[i])
+		}
+	}
+	sortPeople(travelers)
+	locations := []Point{}
+	for i := range travelers {
+		locations = append(locations, travelers[i].CurrentXY)
+	}
+	return locations, nil
+}
+
+// GetPeopleInBuilding return living people inside a building who are not traveling
+func (m *MemoryDAO) GetPeopleInBuilding(buildingid Mongoid) ([]commonModels.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	people := []commonModels.Person{}
+	for i := range m.people {
+		if m.people[i].CurrentBuilding == buildingid.ID && !m.people[i].Traveling && m.people[i].DeathDate.IsZero() {
+			people = append(people, copyPerson(m.people[i]))
+		}
+	}
+	sortPeople(people)
+	return people, nil
+}
+
+// GetResidentsCount get number of living people whose home is in the city
+func (m *MemoryDAO) GetResidentsCount(cityid Mongoid) (int, error) {
+	people, err := m.GetResidents(cityid)
+	return len(people), err
+}
+
+// GetPatientsCount get number of living people admitted to a hospital
+func (m *MemoryDAO) GetPatientsCount(hospitalid Mongoid) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for i := range m.people {
+		if m.people[i].Hospital == hospitalid.ID && m.people[i].DeathDate.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetEmployeesCount get number of living people who work in a building
+func (m *MemoryDAO) GetEmployeesCount(buildingid Mongoid) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for i := range m.people {
+		if m.people[i].WorkBuilding == buildingid.ID && m.people[i].DeathDate.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetTenantsCount get number of living people whose home is a building
+func (m *MemoryDAO) GetTenantsCount(buildingid Mongoid) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for i := range m.people {
+		if m.people[i].HomeBuilding == buildingid.ID && m.people[i].DeathDate.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetResidents get living people whose home is in the city
+func (m *MemoryDAO) GetResidents(cityid Mongoid) ([]commonModels.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	people := []commonModels.Person{}
+	for i := range m.people {
+		home, ok := m.index[COLLECTIONBUILDING][m.people[i].HomeBuilding]
+		if !ok || home >= len(m.buildings) || m.buildings[home].ID != m.people[i].HomeBuilding {
+			continue
+		}
+		if m.buildings[home].CityID == cityid.ID && m.people[i].DeathDate.IsZero() {
+			people = append(people, copyPerson(m.people[i]))
+		}
+	}
+	sortPeople(people)
+	return people, nil
+}
+
+// GetChildren get the IDs of everyone whose mother or father is the parent
+func (m *MemoryDAO) GetChildren(parentid Mongoid) ([]Mongoid, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	children := []Mongoid{}
+	for i := range m.people {
+		if m.people[i].Mother == parentid.ID || m.people[i].Father == parentid.ID {
+			children = append(children, Mongoid{ID: m.people[i].ID})
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+	return children, nil
+}
+
+// CreateCrime Creates a crime in memory
+func (m *MemoryDAO) CreateCrime(crime commonModels.Crime) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONCRIME, &crime.ID, len(m.crimes)); err != nil {
+		return err
+	}
+	m.crimes = append(m.crimes, crime)
+	return nil
+}
+
+// UpdateCrime updates a crime
+func (m *MemoryDAO) UpdateCrime(crime commonModels.Crime) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, ok := m.index[COLLECTIONCRIME][crime.ID]
+	if !ok || i >= len(m.crimes) || m.crimes[i].ID != crime.ID {
+		return mgo.ErrNotFound
+	}
+	m.crimes[i] = crime
+	return nil
+}
+
+// GetCrime get a crime by ID
+func (m *MemoryDAO) GetCrime(id Mongoid) (commonModels.Crime, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.index[COLLECTIONCRIME][id.ID]
+	if !ok || i >= len(m.crimes) || m.crimes[i].ID != id.ID {
+		return commonModels.Crime{}, mgo.ErrNotFound
+	}
+	return m.crimes[i], nil
+}
+
+// GetCrimes get crimes in a city since a time, oldest first
+func (m *MemoryDAO) GetCrimes(cityid Mongoid, since time.Time) ([]commonModels.Crime, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	crimes := []commonModels.Crime{}
+	for i := range m.crimes {
+		if m.crimes[i].CityID == cityid.ID && !m.crimes[i].Time.Before(since) {
+			crimes = append(crimes, m.crimes[i])
+		}
+	}
+	sort.SliceStable(crimes, func(i, j int) bool { return crimes[i].Time.Before(crimes[j].Time) })
+	return crimes, nil
+}
+
+// CreateAccident Creates an accident in memory
+func (m *MemoryDAO) CreateAccident(accident commonModels.CarAccident) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONACCIDENT, &accident.ID, len(m.accidents)); err != nil {
+		return err
+	}
+	m.accidents = append(m.accidents, accident)
+	return nil
+}
+
+// GetAccidents get accidents since a time, oldest first
+func (m *MemoryDAO) GetAccidents(since time.Time) ([]commonModels.CarAccident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	accidents := []commonModels.CarAccident{}
+	for i := range m.accidents {
+		if !m.accidents[i].Time.Before(since) {
+			accidents = append(accidents, m.accidents[i])
+		}
+	}
+	sort.SliceStable(accidents, func(i, j int) bool { return accidents[i].Time.Before(accidents[j].Time) })
+	return accidents, nil
+}
+
+// CreateHighway Creates a highway in memory
+func (m *MemoryDAO) CreateHighway(highway commonModels.HighwayRoute) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.insert(COLLECTIONHIGHWAY, &highway.ID, len(m.highways)); err != nil {
+		return err
+	}
+	m.highways = append(m.highways, highway)
+	return nil
+}
+
+// GetAllHighways get every highway
+func (m *MemoryDAO) GetAllHighways() ([]commonModels.HighwayRoute, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	highways := append([]commonModels.HighwayRoute{}, m.highways...)
+	sort.Slice(highways, func(i, j int) bool { return highways[i].ID < highways[j].ID })
+	return highways, nil
+}
+
+// RecordDiseaseStat add the counts to the disease's stats for the day
+func (m *MemoryDAO) RecordDiseaseStat(stat commonModels.DiseaseStat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.stats {
+		if m.stats[i].DiseaseID == stat.DiseaseID && m.stats[i].Day.Equal(stat.Day) {
+			m.stats[i].NewCases += stat.NewCases
+			m.stats[i].Recoveries += stat.Recoveries
+			m.stats[i].Deaths += stat.Deaths
+			return nil
+		}
+	}
+	m.stats = append(m.stats, stat)
+	sort.SliceStable(m.stats, func(i, j int) bool { return m.stats[i].Day.Before(m.stats[j].Day) })
+	return nil
+}
+
+// GetDiseaseStats get the daily stats of a disease, oldest first
+func (m *MemoryDAO) GetDiseaseStats(diseaseid Mongoid) ([]commonModels.DiseaseStat, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stats := []commonModels.DiseaseStat{}
+	for i := range m.stats {
+		if m.stats[i].DiseaseID == diseaseid.ID {
+			stats = append(stats, m.stats[i])
+		}
+	}
+	return stats, nil
+}
+
+// GetTerrainTile get the terrain tile at x, y
+func (m *MemoryDAO) GetTerrainTile(x int, y int) (commonModels.TerrainTile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tile, ok := m.terrain[Point{X: x, Y: y}]
+	if !ok {
+		return tile, mgo.ErrNotFound
+	}
+	tile.Elevation = append([]int{}, tile.Elevation...)
+	tile.Cover = append([]commonModels.LandCover{}, tile.Cover...)
+	return tile, nil
+}
+
+// SaveTerrainTile save a terrain tile, replacing any already at its x, y
+func (m *MemoryDAO) SaveTerrainTile(tile commonModels.TerrainTile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tile.Elevation = append([]int{}, tile.Elevation...)
+	tile.Cover = append([]commonModels.LandCover{}, tile.Cover...)
+	m.terrain[Point{X: tile.X, Y: tile.Y}] = tile
+	return nil
+}
+
+// SaveCityStat save the city's stats for the day, replacing any already saved
+func (m *MemoryDAO) SaveCityStat(stat commonModels.CityStat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.cityStats {
+		if m.cityStats[i].CityID == stat.CityID && m.cityStats[i].Day.Equal(stat.Day) {
+			stat.ID = m.cityStats[i].ID
+			m.cityStats[i] = stat
+			return nil
+		}
+	}
+	m.cityStats = append(m.cityStats, stat)
+	sort.SliceStable(m.cityStats, func(i, j int) bool { return m.cityStats[i].Day.Before(m.cityStats[j].Day) })
+	return nil
+}
+
+// GetCityStats get the daily stats of a city, oldest first
+func (m *MemoryDAO) GetCityStats(cityid Mongoid) ([]commonModels.CityStat, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	stats := []commonModels.CityStat{}
+	for i := range m.cityStats {
+		if m.cityStats[i].CityID == cityid.ID {
+			stats = append(stats, m.cityStats[i])
+		}
+	}
+	return stats, nil
+}
+
+// SaveSettings save settings
+func (m *MemoryDAO) SaveSettings(settings commonModels.Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.settings {
+		if m.settings[i].ID == settings.ID {
+			m.settings[i] = settings
+			return nil
+		}
+	}
+	return mgo.ErrNotFound
+}
+
+// LoadSettings load the first settings document
+func (m *MemoryDAO) LoadSettings() (commonModels.Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.settings) > 0 {
+		return m.settings[0], nil
+	}
+	return commonModels.Settings{}, nil
+}
+
+// InsertSettings create settings
+func (m *MemoryDAO) InsertSettings(settings commonModels.Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if settings.ID == "" {
+		settings.ID = bson.NewObjectId()
+	}
+	m.settings = append(m.settings, settings)
+	return nil
+}
+
+// sortPeople put people in _id order
+func sortPeople(people []commonModels.Person) {
+	sort.Slice(people, func(i, j int) bool { return people[i].ID < people[j].ID })
+}
+
+// copyPerson copy a person so callers never share slices with the store
+func copyPerson(person commonModels.Person) commonModels.Person {
+	if person.ChildrenIDs != nil {
+		person.ChildrenIDs = append([]bson.ObjectId{}, person.ChildrenIDs...)
+	}
+	if person.Immunities != nil {
+		person.Immunities = append([]bson.ObjectId{}, person.Immunities...)
+	}
+	if person.Route != nil {
+		person.Route = append([]commonModels.Waypoint{}, person.Route...)
+	}
+	return person
+}
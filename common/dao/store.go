@@ -0,0 +1,72 @@
+package dao
+
+import (
+	. "image"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+// WorldStore storage used by the controllers and workers, implemented by the
+// Mongo backed DAO and the in-memory MemoryDAO
+type WorldStore interface {
+	Connect()
+
+	CreateCity(city commonModels.City) error
+	UpdateCity(city commonModels.City) error
+	AdjustCityBalance(cityid Mongoid, amount int) error
+	GetCity(id Mongoid) (commonModels.City, error)
+	GetCitiesCount() (int, error)
+	GetAllCityIDs() ([]Mongoid, error)
+	GetAllCities() ([]commonModels.City, error)
+
+	CreateBuilding(building commonModels.Building) error
+	UpdateBuilding(building commonModels.Building) error
+	GetBuilding(id Mongoid) (commonModels.Building, error)
+	GetBuildingsCount() (int, error)
+	GetAllBuildingIDs(cityid Mongoid) ([]Mongoid, error)
+	GetBuildings(cityid Mongoid) ([]commonModels.Building, error)
+
+	CreatePerson(person commonModels.Person) error
+	UpdatePerson(person commonModels.Person) error
+	DispatchOfficer(officer commonModels.Person, stationid Mongoid) error
+	HarmPerson(victim commonModels.Person) error
+	GetPerson(id Mongoid) (commonModels.Person, error)
+	GetPeopleCount() (int, error)
+	GetAllTravelers() ([]Mongoid, error)
+	GetTravelerLocations() ([]Point, error)
+	GetPeopleInBuilding(buildingid Mongoid) ([]commonModels.Person, error)
+	GetResidentsCount(cityid Mongoid) (int, error)
+	GetPatientsCount(hospitalid Mongoid) (int, error)
+	GetEmployeesCount(buildingid Mongoid) (int, error)
+	GetTenantsCount(buildingid Mongoid) (int, error)
+	GetResidents(cityid Mongoid) ([]commonModels.Person, error)
+	GetChildren(parentid Mongoid) ([]Mongoid, error)
+
+	CreateHighway(highway commonModels.HighwayRoute) error
+	GetAllHighways() ([]commonModels.HighwayRoute, error)
+
+	CreateCrime(crime commonModels.Crime) error
+	UpdateCrime(crime commonModels.Crime) error
+	GetCrime(id Mongoid) (commonModels.Crime, error)
+	GetCrimes(cityid Mongoid, since time.Time) ([]commonModels.Crime, error)
+
+	CreateAccident(accident commonModels.CarAccident) error
+	GetAccidents(since time.Time) ([]commonModels.CarAccident, error)
+
+	RecordDiseaseStat(stat commonModels.DiseaseStat) error
+	GetDiseaseStats(diseaseid Mongoid) ([]commonModels.DiseaseStat, error)
+
+	GetTerrainTile(x int, y int) (commonModels.TerrainTile, error)
+	SaveTerrainTile(tile commonModels.TerrainTile) error
+
+	SaveCityStat(stat commonModels.CityStat) error
+	GetCityStats(cityid Mongoid) ([]commonModels.CityStat, error)
+
+	SaveSettings(settings commonModels.Settings) error
+	LoadSettings() (commonModels.Settings, error)
+	InsertSettings(settings commonModels.Settings) error
+}
+
+var _ WorldStore = (*DAO)(nil)
+var _ WorldStore = (*MemoryDAO)(nil)
@@ -0,0 +1,288 @@
+package dao
+
+import (
+	. "image"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// stores the backends to compare, MongoDB is only tested when
+// DAWS_TEST_MONGO names a server whose daws_test database can be dropped
+func stores(t *testing.T) map[string]WorldStore {
+	backends := map[string]WorldStore{"memory": NewMemoryDAO()}
+	if server := os.Getenv("DAWS_TEST_MONGO"); server != "" {
+		mongo := &DAO{Server: server, Database: "daws_test"}
+		mongo.Connect()
+		if err := db.DropDatabase(); err != nil {
+			t.Fatalf("Failed to drop test database: %s", err)
+		}
+		backends["mongo"] = mongo
+	}
+	return backends
+}
+
+// fixture two cities whose buildings and people are created out of _id order
+type fixture struct {
+	cities    []commonModels.City
+	buildings []commonModels.Building
+	people    []commonModels.Person
+}
+
+func newFixture() fixture {
+	rng := random.New(42, "store test")
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := fixture{}
+	for c := 0; c < 2; c++ {
+		f.cities = append(f.cities, commonModels.City{ID: random.ObjectID(rng), Name: "City", Established: now})
+	}
+	for b := 0; b < 6; b++ {
+		f.buildings = append(f.buildings, commonModels.Building{
+			ID:        random.ObjectID(rng),
+			Name:      "Building",
+			Type:      commonModels.House,
+			TopLeft:   Point{X: b * 100, Y: 0},
+			BuildDate: now,
+			CityID:    f.cities[b%2].ID,
+		})
+	}
+	for p := 0; p < 30; p++ {
+		person := commonModels.Person{
+			ID:              random.ObjectID(rng),
+			Birthdate:       now.AddDate(-30, 0, 0),
+			FirstName:       "Person",
+			ChildrenIDs:     []bson.ObjectId{},
+			HomeBuilding:    f.buildings[p%len(f.buildings)].ID,
+			CurrentBuilding: f.buildings[(p/2)%len(f.buildings)].ID,
+			Traveling:       p%5 == 0,
+			Health:          100,
+		}
+		if p%7 == 0 {
+			person.DeathDate = now
+		}
+		f.people = append(f.people, person)
+	}
+	return f
+}
+
+func (f fixture) load(t *testing.T, store WorldStore) {
+	for _, c := range f.cities {
+		if err := store.CreateCity(c); err != nil {
+			t.Fatalf("Failed to create city: %s", err)
+		}
+	}
+	for _, b := range f.buildings {
+		if err := store.CreateBuilding(b); err != nil {
+			t.Fatalf("Failed to create building: %s", err)
+		}
+	}
+	for _, p := range f.people {
+		if err := store.CreatePerson(p); err != nil {
+			t.Fatalf("Failed to create person: %s", err)
+		}
+	}
+}
+
+// results what the tick reads from a store, in the order it reads it
+type results struct {
+	BuildingIDs [][]Mongoid
+	Residents   [][]bson.ObjectId
+	InBuilding  [][]bson.ObjectId
+}
+
+func query(t *testing.T, store WorldStore, f fixture) results {
+	r := results{}
+	for _, c := range f.cities {
+		ids, err := store.GetAllBuildingIDs(Mongoid{ID: c.ID})
+		if err != nil {
+			t.Fatalf("GetAllBuildingIDs: %s", err)
+		}
+		r.BuildingIDs = append(r.BuildingIDs, ids)
+		residents, err := store.GetResidents(Mongoid{ID: c.ID})
+		if err != nil {
+			t.Fatalf("GetResidents: %s", err)
+		}
+		r.Residents = append(r.Residents, personIDs(residents))
+	}
+	for _, b := range f.buildings {
+		occupants, err := store.GetPeopleInBuilding(Mongoid{ID: b.ID})
+		if err != nil {
+			t.Fatalf("GetPeopleInBuilding: %s", err)
+		}
+		r.InBuilding = append(r.InBuilding, personIDs(occupants))
+	}
+	return r
+}
+
+func personIDs(people []commonModels.Person) []bson.ObjectId {
+	ids := []bson.ObjectId{}
+	for _, p := range people {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestQueriesSortByID(t *testing.T) {
+	f := newFixture()
+	for name, store := range stores(t) {
+		f.load(t, store)
+		r := query(t, store, f)
+		for _, ids := range r.BuildingIDs {
+			if len(ids) != 3 {
+				t.Errorf("%s: expected 3 buildings in each city, got %d", name, len(ids))
+			}
+			for i := 1; i < len(ids); i++ {
+				if ids[i-1].ID >= ids[i].ID {
+					t.Errorf("%s: building IDs out of _id order", name)
+				}
+			}
+		}
+		for _, lists := range [][][]bson.ObjectId{r.Residents, r.InBuilding} {
+			for _, ids := range lists {
+				for i := 1; i < len(ids); i++ {
+					if ids[i-1] >= ids[i] {
+						t.Errorf("%s: people out of _id order", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQueriesSkipTheDeadAndTravelers(t *testing.T) {
+	f := newFixture()
+	for name, store := range stores(t) {
+		f.load(t, store)
+		r := query(t, store, f)
+		residents, inBuildings := 0, 0
+		for _, ids := range r.Residents {
+			residents += len(ids)
+		}
+		for _, ids := range r.InBuilding {
+			inBuildings += len(ids)
+		}
+		alive, inside := 0, 0
+		for _, p := range f.people {
+			if p.DeathDate.IsZero() {
+				alive++
+				if !p.Traveling {
+					inside++
+				}
+			}
+		}
+		if residents != alive {
+			t.Errorf("%s: expected %d residents, got %d", name, alive, residents)
+		}
+		if inBuildings != inside {
+			t.Errorf("%s: expected %d people in buildings, got %d", name, inside, inBuildings)
+		}
+	}
+}
+
+func TestBackendsAgree(t *testing.T) {
+	backends := stores(t)
+	if len(backends) < 2 {
+		t.Skip("set DAWS_TEST_MONGO to compare against MongoDB")
+	}
+	f := newFixture()
+	f.load(t, backends["memory"])
+	f.load(t, backends["mongo"])
+	memory := query(t, backends["memory"], f)
+	mongo := query(t, backends["mongo"], f)
+	if !reflect.DeepEqual(memory, mongo) {
+		t.Errorf("backends disagree:\nmemory %v\nmongo  %v", memory, mongo)
+	}
+}
+
+func TestDispatchOfficerOnlyIfStillAtStation(t *testing.T) {
+	f := newFixture()
+	for name, store := range stores(t) {
+		f.load(t, store)
+		officer := f.people[1]
+		station := Mongoid{ID: officer.CurrentBuilding}
+		officer.Responding = random.ObjectID(random.New(1, "crime"))
+		officer.Traveling = true
+		officer.Destination = Point{X: 500, Y: 500}
+		if err := store.DispatchOfficer(officer, station); err != nil {
+			t.Fatalf("%s: first dispatch failed: %s", name, err)
+		}
+		saved, err := store.GetPerson(Mongoid{ID: officer.ID})
+		if err != nil {
+			t.Fatalf("%s: GetPerson: %s", name, err)
+		}
+		if saved.Responding != officer.Responding || !saved.Traveling || saved.Destination != officer.Destination {
+			t.Errorf("%s: dispatch not saved: %+v", name, saved)
+		}
+		if saved.Health != f.people[1].Health || saved.FirstName != f.people[1].FirstName {
+			t.Errorf("%s: dispatch changed fields it doesn't own", name)
+		}
+		officer.Responding = random.ObjectID(random.New(2, "crime"))
+		if err := store.DispatchOfficer(officer, station); err != mgo.ErrNotFound {
+			t.Errorf("%s: expected a second dispatch to find nobody, got %v", name, err)
+		}
+	}
+}
+
+func TestHarmPersonOnlyWritesHarm(t *testing.T) {
+	f := newFixture()
+	for name, store := range stores(t) {
+		f.load(t, store)
+		victim := f.people[1]
+		victim.Health = 10
+		victim.Happiness = 5
+		victim.FirstName = "Changed"
+		if err := store.HarmPerson(victim); err != nil {
+			t.Fatalf("%s: HarmPerson: %s", name, err)
+		}
+		saved, err := store.GetPerson(Mongoid{ID: victim.ID})
+		if err != nil {
+			t.Fatalf("%s: GetPerson: %s", name, err)
+		}
+		if saved.Health != 10 || saved.Happiness != 5 || saved.FirstName != f.people[1].FirstName {
+			t.Errorf("%s: expected only health and happiness to change: %+v", name, saved)
+		}
+		if err := store.HarmPerson(f.people[0]); err != mgo.ErrNotFound {
+			t.Errorf("%s: expected a dead traveler to be left alone, got %v", name, err)
+		}
+		if err := store.HarmPerson(f.people[5]); err != mgo.ErrNotFound {
+			t.Errorf("%s: expected a traveler to be left alone, got %v", name, err)
+		}
+	}
+}
+
+func TestCreateLikeMongo(t *testing.T) {
+	for name, store := range stores(t) {
+		before, err := store.GetPeopleCount()
+		if err != nil {
+			t.Fatalf("%s: GetPeopleCount: %s", name, err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := store.CreatePerson(commonModels.Person{FirstName: "Nobody", ChildrenIDs: []bson.ObjectId{}}); err != nil {
+				t.Errorf("%s: person without an ID: %s", name, err)
+			}
+		}
+		if after, _ := store.GetPeopleCount(); after != before+2 {
+			t.Errorf("%s: expected 2 more people, got %d", name, after-before)
+		}
+
+		shared := random.ObjectID(random.New(3, "shared id"))
+		if err := store.CreateCity(commonModels.City{ID: shared, Name: "City"}); err != nil {
+			t.Fatalf("%s: CreateCity: %s", name, err)
+		}
+		if err := store.CreateBuilding(commonModels.Building{ID: shared, Name: "Building", CityID: shared}); err != nil {
+			t.Errorf("%s: a building sharing a city's ID should be its own record: %s", name, err)
+		}
+		if err := store.CreateCity(commonModels.City{ID: shared, Name: "Again"}); !mgo.IsDup(err) {
+			t.Errorf("%s: expected a duplicate city to fail, got %v", name, err)
+		}
+		if city, err := store.GetCity(Mongoid{ID: shared}); err != nil || city.Name != "City" {
+			t.Errorf("%s: expected the first city back, got %+v %v", name, city, err)
+		}
+	}
+}
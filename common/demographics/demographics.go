@@ -0,0 +1,163 @@
+package demographics
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/names"
+	"github.com/toasterlint/DAWS/common/random"
+	"github.com/toasterlint/DAWS/common/schedule"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// DAYSPERYEAR used to turn yearly rates into daily chances
+	DAYSPERYEAR = 365.25
+	// RETIREMENTAGE age people stop working
+	RETIREMENTAGE = 67
+)
+
+// band a yearly rate that applies from an age until the next band
+type band struct {
+	age  int
+	rate float64
+}
+
+// mortality chance of dying within the year, roughly a modern life table
+var mortality = []band{
+	{0, 0.0056}, {1, 0.0002}, {15, 0.0007}, {25, 0.0013}, {35, 0.0020},
+	{45, 0.0040}, {55, 0.0090}, {65, 0.0180}, {75, 0.0450}, {85, 0.1300}, {95, 0.3000},
+}
+
+// fertility births per woman per year with a living spouse
+var fertility = []band{
+	{0, 0}, {15, 0.02}, {20, 0.08}, {25, 0.11}, {30, 0.10}, {35, 0.05}, {40, 0.01}, {45, 0},
+}
+
+func lookup(bands []band, age int) float64 {
+	rate := 0.0
+	for _, b := range bands {
+		if age >= b.age {
+			rate = b.rate
+		}
+	}
+	return rate
+}
+
+// Alive whether the person hasn't died yet
+func Alive(person commonModels.Person) bool {
+	return person.DeathDate.IsZero()
+}
+
+// Age age in whole years at t
+func Age(person commonModels.Person, t time.Time) int {
+	years := t.Year() - person.Birthdate.Year()
+	if t.Month() < person.Birthdate.Month() || (t.Month() == person.Birthdate.Month() && t.Day() < person.Birthdate.Day()) {
+		years--
+	}
+	if years < 0 {
+		return 0
+	}
+	return years
+}
+
+// Adult old enough to marry and work
+func Adult(person commonModels.Person, t time.Time) bool {
+	return Age(person, t) >= 18
+}
+
+// WorkingAge whether a person is an adult who hasn't reached retirement
+func WorkingAge(person commonModels.Person, t time.Time) bool {
+	return Adult(person, t) && Age(person, t) < RETIREMENTAGE
+}
+
+// NewDay whether t is the first tick of a simulated day, when the cities do
+// their daily bookkeeping
+func NewDay(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
+
+// Day midnight at the start of t's simulated day
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// DailyDue whether a living person's daily events haven't been rolled yet on
+// t's day. Most people are seen on the first tick of the day, anyone away
+// then is seen the first time they are back in a building.
+func DailyDue(person commonModels.Person, t time.Time) bool {
+	return Alive(person) && person.LastDay.Before(Day(t))
+}
+
+// Daily turn a yearly chance into the chance for a single day
+func Daily(yearly float64) float64 {
+	if yearly >= 1 {
+		return 1
+	}
+	return 1 - math.Pow(1-yearly, 1/DAYSPERYEAR)
+}
+
+// MortalityRate chance of a natural death within the year at age
+func MortalityRate(age int) float64 {
+	return lookup(mortality, age)
+}
+
+// FertilityRate births per year for a woman of age
+func FertilityRate(age int) float64 {
+	return lookup(fertility, age)
+}
+
+// DiesNaturally roll for a natural death today
+func DiesNaturally(rng *rand.Rand, person commonModels.Person, t time.Time) bool {
+	return Alive(person) && rng.Float64() < Daily(MortalityRate(Age(person, t)))
+}
+
+// HasChild roll for a spouse pair having a child today, mother must be the
+// female half of the pair
+func HasChild(rng *rand.Rand, mother commonModels.Person, father commonModels.Person, t time.Time) bool {
+	if !Alive(mother) || !Alive(father) || mother.Gender != commonModels.Female {
+		return false
+	}
+	if mother.Spouse != father.ID || father.Spouse != mother.ID || !Adult(father, t) {
+		return false
+	}
+	return rng.Float64() < Daily(FertilityRate(Age(mother, t)))
+}
+
+// Die record a death
+func Die(person *commonModels.Person, t time.Time, cause commonModels.DeathType) {
+	person.DeathDate = t
+	person.CauseOfDeath = cause
+	person.Traveling = false
+	person.Route = nil
+}
+
+// Child a newborn of the pair, living where the mother lives and taking the
+// father's surname. The parents' ChildrenIDs are updated, their first daily
+// events are rolled tomorrow.
+func Child(rng *rand.Rand, gen *names.Generator, mother *commonModels.Person, father *commonModels.Person, t time.Time) commonModels.Person {
+	child := commonModels.Person{}
+	child.ID = random.ObjectID(rng)
+	child.Birthdate = t
+	child.Gender = commonModels.Male
+	if rng.Intn(2) == 0 {
+		child.Gender = commonModels.Female
+	}
+	child.FirstName = gen.FirstName(child.Gender)
+	child.LastName = father.LastName
+	child.ChildrenIDs = []bson.ObjectId{}
+	child.CurrentBuilding = mother.CurrentBuilding
+	child.CurrentXY = mother.CurrentXY
+	child.HomeBuilding = mother.HomeBuilding
+	child.Health = 100
+	child.Happiness = 100
+	child.Schedule = schedule.New(rng)
+	child.Mother = mother.ID
+	child.Father = father.ID
+	child.LastDay = Day(t)
+	mother.ChildrenIDs = append(mother.ChildrenIDs, child.ID)
+	father.ChildrenIDs = append(father.ChildrenIDs, child.ID)
+	return child
+}
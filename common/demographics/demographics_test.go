@@ -0,0 +1,36 @@
+package demographics
+
+import (
+	"testing"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthdate time.Time
+		at        time.Time
+		want      int
+	}{
+		{"day before birthday", date(2000, time.June, 15), date(2020, time.June, 14), 19},
+		{"on birthday", date(2000, time.June, 15), date(2020, time.June, 15), 20},
+		{"born in a leap year, birthday in a common year", date(2000, time.March, 1), date(2021, time.March, 1), 21},
+		{"born in a leap year, day before in a common year", date(2000, time.March, 1), date(2021, time.February, 28), 20},
+		{"born in a common year, birthday in a leap year", date(2001, time.March, 1), date(2020, time.March, 1), 19},
+		{"born in a common year, day before in a leap year", date(2001, time.March, 1), date(2020, time.February, 29), 18},
+		{"leap day, not yet in a common year", date(2000, time.February, 29), date(2021, time.February, 28), 20},
+		{"leap day, past it in a common year", date(2000, time.February, 29), date(2021, time.March, 1), 21},
+		{"not born yet", date(2020, time.June, 15), date(2019, time.June, 15), 0},
+	}
+	for _, tt := range tests {
+		if got := Age(commonModels.Person{Birthdate: tt.birthdate}, tt.at); got != tt.want {
+			t.Errorf("%s: Age %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
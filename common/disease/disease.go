@@ -0,0 +1,170 @@
+package disease
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Outcome how a day of illness turned out
+type Outcome int
+
+const (
+	// StillIll nothing changed yet
+	StillIll Outcome = iota
+	// Recovered the illness ran its course
+	Recovered
+	// Died the illness was fatal
+	Died
+	// Unknown the disease isn't in the world's settings, the illness was dropped
+	Unknown
+)
+
+// Defaults diseases for a new world, IDs derived from the world seed
+func Defaults(seed int64) []commonModels.Disease {
+	diseases := []commonModels.Disease{
+		{Name: "Common Cold", DaysDetected: 2, AvgDaysIll: 7, LethalityRate: 0, Infectious: true, InfectionChance: 0.02, Severity: 0.05, OnsetRate: 0.5},
+		{Name: "Influenza", DaysDetected: 2, AvgDaysIll: 7, LethalityRate: 0.001, Infectious: true, InfectionChance: 0.03, Severity: 0.2, OnsetRate: 0.05},
+		{Name: "Measles", DaysDetected: 10, AvgDaysIll: 8, LethalityRate: 0.002, Infectious: true, InfectionChance: 0.1, Severity: 0.3, OnsetRate: 0.0001},
+		{Name: "Pneumonia", DaysDetected: 3, AvgDaysIll: 14, LethalityRate: 0.05, Infectious: false, InfectionChance: 0, Severity: 0.4, OnsetRate: 0.005},
+	}
+	for i := range diseases {
+		diseases[i].ID = random.ObjectID(random.New(seed, "disease", diseases[i].Name))
+	}
+	return diseases
+}
+
+// Find the world's disease with the ID
+func Find(settings commonModels.Settings, id bson.ObjectId) (commonModels.Disease, bool) {
+	for _, d := range settings.Diseases {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return commonModels.Disease{}, false
+}
+
+// Ill whether the person currently has a disease
+func Ill(person commonModels.Person) bool {
+	return person.Illness.Valid()
+}
+
+// Immune whether the person has had the disease before
+func Immune(person commonModels.Person, d commonModels.Disease) bool {
+	for _, id := range person.Immunities {
+		if id == d.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Susceptible whether the person can catch the disease
+func Susceptible(person commonModels.Person, d commonModels.Disease) bool {
+	return demographics.Alive(person) && !Ill(person) && !Immune(person, d)
+}
+
+// Contagious whether the person can pass on their illness
+func Contagious(settings commonModels.Settings, person commonModels.Person) (commonModels.Disease, bool) {
+	if !Ill(person) || !demographics.Alive(person) {
+		return commonModels.Disease{}, false
+	}
+	d, ok := Find(settings, person.Illness)
+	return d, ok && d.Infectious
+}
+
+// Detected whether the illness has been noticed, after which the person stays
+// home instead of going about their day
+func Detected(settings commonModels.Settings, person commonModels.Person, t time.Time) bool {
+	if !Ill(person) {
+		return false
+	}
+	d, ok := Find(settings, person.Illness)
+	if !ok {
+		return false
+	}
+	return !t.Before(person.IllSince.AddDate(0, 0, d.DaysDetected))
+}
+
+// Infect give the person the disease, lasting between half and one and a half
+// times its average
+func Infect(rng *rand.Rand, person *commonModels.Person, d commonModels.Disease, t time.Time) {
+	days := float64(d.AvgDaysIll) * (0.5 + rng.Float64())
+	person.Illness = d.ID
+	person.IllSince = t
+	person.IllUntil = t.Add(time.Duration(days * 24 * float64(time.Hour)))
+}
+
+// Spread one hour of contact between the occupants of a building, returning
+// the indexes of the newly infected
+func Spread(rng *rand.Rand, settings commonModels.Settings, occupants []commonModels.Person, t time.Time) []int {
+	infected := []int{}
+	for i := range occupants {
+		d, ok := Contagious(settings, occupants[i])
+		if !ok || !occupants[i].IllSince.Before(t) {
+			continue
+		}
+		for j := range occupants {
+			if i == j || !Susceptible(occupants[j], d) {
+				continue
+			}
+			if rng.Float64() < float64(d.InfectionChance) {
+				Infect(rng, &occupants[j], d, t)
+				infected = append(infected, j)
+			}
+		}
+	}
+	return infected
+}
+
+// Onset roll for catching a disease from outside the world today
+func Onset(rng *rand.Rand, settings commonModels.Settings, person *commonModels.Person, t time.Time) (commonModels.Disease, bool) {
+	for _, d := range settings.Diseases {
+		if Susceptible(*person, d) && rng.Float64() < demographics.Daily(float64(d.OnsetRate)) {
+			Infect(rng, person, d, t)
+			return d, true
+		}
+	}
+	return commonModels.Disease{}, false
+}
+
+// Progress one day of illness, lethality scales how deadly the disease is
+// for this person, 1 being untreated
+func Progress(rng *rand.Rand, settings commonModels.Settings, person *commonModels.Person, t time.Time, lethality float64) (commonModels.Disease, Outcome) {
+	d, ok := Find(settings, person.Illness)
+	if !ok {
+		d.ID = person.Illness
+		person.Illness = ""
+		person.IllSince = time.Time{}
+		person.IllUntil = time.Time{}
+		return d, Unknown
+	}
+	person.Health -= int(math.Round(float64(d.Severity) * 10))
+	if person.Health <= 0 {
+		person.Health = 0
+		demographics.Die(person, t, commonModels.Illness)
+		return d, Died
+	}
+	if t.Before(person.IllUntil) {
+		return d, StillIll
+	}
+	if rng.Float64() < float64(d.LethalityRate)*lethality {
+		demographics.Die(person, t, commonModels.Illness)
+		return d, Died
+	}
+	person.Illness = ""
+	person.IllSince = time.Time{}
+	person.IllUntil = time.Time{}
+	person.Immunities = append(person.Immunities, d.ID)
+	return d, Recovered
+}
+
+// Day the simulated day t falls on, used to group stats
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
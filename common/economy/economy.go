@@ -0,0 +1,158 @@
+// Package economy wages, rent and spending of the people in the world. Each
+// city keeps a balance that stands in for its employers and landlords: wages
+// are paid out of the balance of the city the job is in, and rent and
+// spending are paid into the city where they happen, so money only moves
+// between people and cities.
+package economy
+
+import (
+	"sort"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// STARTINGBALANCE dollars each of the first settlers has saved up
+	STARTINGBALANCE = 5000
+	// POVERTYLINE yearly income below which a working age adult is counted as
+	// poor
+	POVERTYLINE = 15000
+	// SAVINGSDAYS days of rent in the bank that make someone feel secure
+	SAVINGSDAYS = 30
+)
+
+// Wage yearly dollars earned working in a type of building, more for those
+// with more education
+func Wage(buildingType commonModels.BuildingType, level commonModels.EducationLevel) int {
+	base := 0
+	switch buildingType {
+	case commonModels.Office:
+		base = 40000
+	case commonModels.Hospital:
+		base = 45000
+	case commonModels.School, commonModels.Police:
+		base = 38000
+	case commonModels.Warehouse:
+		base = 30000
+	case commonModels.Retail, commonModels.Entertainment:
+		base = 24000
+	}
+	if base == 0 {
+		return 0
+	}
+	return base + int(level)*5000
+}
+
+// DailyWage dollars a worker is paid each day
+func DailyWage(person commonModels.Person) int {
+	return int(float64(person.Wage) / demographics.DAYSPERYEAR)
+}
+
+// Rent dollars a day each adult pays to live in a building, 0 if it isn't a
+// home
+func Rent(building commonModels.Building) int {
+	switch building.Type {
+	case commonModels.House:
+		return 40
+	case commonModels.Apartment:
+		return 30
+	}
+	return 0
+}
+
+// Price dollars an hour a visitor spends in a building, 0 if there's nothing
+// to buy
+func Price(building commonModels.Building) int {
+	switch building.Type {
+	case commonModels.Retail:
+		return 15
+	case commonModels.Entertainment:
+		return 25
+	}
+	return 0
+}
+
+// Payday pay a day's wage and charge a day's rent for a resident of home,
+// returning both so the employer's city can be charged the wage and the
+// home's city credited the rent. Children don't pay rent.
+func Payday(person *commonModels.Person, home commonModels.Building, t time.Time) (wage int, rent int) {
+	wage = DailyWage(*person)
+	if demographics.Adult(*person, t) {
+		rent = Rent(home)
+	}
+	person.Balance += wage - rent
+	return wage, rent
+}
+
+// Spend an hour's spending at a building, if they can afford it. Returns
+// whether they spent anything.
+func Spend(person *commonModels.Person, building commonModels.Building) bool {
+	price := Price(building)
+	if price == 0 || person.Balance < price || person.WorkBuilding == building.ID {
+		return false
+	}
+	person.Balance -= price
+	return true
+}
+
+// Mood how a day of finances shifts someone's happiness, debt and no income
+// hurt and savings help
+func Mood(person commonModels.Person, home commonModels.Building, t time.Time) int {
+	if !demographics.Adult(person, t) {
+		return 0
+	}
+	mood := 0
+	if person.Balance < 0 {
+		mood -= 3
+	} else if person.Balance >= SAVINGSDAYS*Rent(home) {
+		mood++
+	}
+	if person.Wage == 0 {
+		mood--
+	}
+	return mood
+}
+
+// Stat add the economic figures of a city's residents at t to its stats. GDP
+// is the yearly wages of everyone in work, median income is over adults in
+// work and the poverty rate is the share of working age adults out of school
+// earning under POVERTYLINE, so retirees and students aren't counted as poor.
+func Stat(stat *commonModels.CityStat, residents []commonModels.Person, t time.Time) {
+	incomes := []int{}
+	adults, poor := 0, 0
+	stat.GDP = 0
+	for _, p := range residents {
+		if !demographics.Adult(p, t) {
+			continue
+		}
+		if demographics.WorkingAge(p, t) && !p.School.Valid() {
+			adults++
+			if p.Wage < POVERTYLINE {
+				poor++
+			}
+		}
+		if p.Wage > 0 {
+			stat.GDP += p.Wage
+			incomes = append(incomes, p.Wage)
+		}
+	}
+	stat.MedianIncome = median(incomes)
+	stat.PovertyRate = 0
+	if adults > 0 {
+		stat.PovertyRate = float64(poor) / float64(adults)
+	}
+}
+
+func median(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Ints(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
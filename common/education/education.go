@@ -0,0 +1,133 @@
+package education
+
+import (
+	. "image"
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/schedule"
+)
+
+const (
+	// PRIMARYAGE age children start school
+	PRIMARYAGE = 5
+	// SECONDARYAGE age children move up from primary school
+	SECONDARYAGE = 12
+	// COLLEGEAGE age students leave secondary school
+	COLLEGEAGE = 18
+	// GRADUATEAGE age students finish college
+	GRADUATEAGE = 22
+	// COLLEGECHANCE chance a secondary school leaver goes on to college
+	COLLEGECHANCE = 0.4
+)
+
+// Schools schools in the list that are open at t
+func Schools(buildings []commonModels.Building, t time.Time) []commonModels.Building {
+	schools := []commonModels.Building{}
+	for _, b := range buildings {
+		if b.Type == commonModels.School && !b.BuildDate.After(t) {
+			schools = append(schools, b)
+		}
+	}
+	return schools
+}
+
+// Schedule the school day, children are home by mid afternoon and in bed
+// early
+func Schedule() commonModels.Schedule {
+	return commonModels.Schedule{
+		Wake:      7 * 60,
+		WorkStart: 8 * 60,
+		WorkEnd:   15 * 60,
+		Bedtime:   21 * 60,
+		Commute:   20,
+		Weekends:  false,
+	}
+}
+
+// Daily a day of schooling for a person, enrolling children who are due and
+// moving students up a level or out of school. Whether a secondary school
+// leaver goes on to college is rolled with rng. Returns whether anything
+// changed.
+func Daily(rng *rand.Rand, person *commonModels.Person, schools []commonModels.Building, home Point, t time.Time) bool {
+	if !demographics.Alive(*person) {
+		return false
+	}
+	age := demographics.Age(*person, t)
+	if !person.School.Valid() {
+		if age < PRIMARYAGE || age >= COLLEGEAGE {
+			return false
+		}
+		return Enroll(person, schools, home)
+	}
+	switch {
+	case age >= GRADUATEAGE && person.Education == commonModels.Secondary:
+		person.Education = commonModels.College
+		Leave(rng, person)
+	case age >= COLLEGEAGE && person.Education < commonModels.Secondary:
+		person.Education = commonModels.Secondary
+		if rng.Float64() >= COLLEGECHANCE {
+			Leave(rng, person)
+		}
+	case age >= SECONDARYAGE && person.Education < commonModels.Primary:
+		person.Education = commonModels.Primary
+	default:
+		return false
+	}
+	return true
+}
+
+// Enroll put a person in the nearest school to home
+func Enroll(person *commonModels.Person, schools []commonModels.Building, home Point) bool {
+	if len(schools) == 0 {
+		return false
+	}
+	school := geometry.Nearest(schools, home)[0]
+	commute := person.Schedule.Commute
+	person.School = school.ID
+	person.Schedule = Schedule()
+	if commute > 0 {
+		person.Schedule.Commute = commute
+	}
+	return true
+}
+
+// Leave take a person out of school and give them a working routine
+func Leave(rng *rand.Rand, person *commonModels.Person) {
+	commute := person.Schedule.Commute
+	person.School = ""
+	person.Schedule = schedule.New(rng)
+	person.Schedule.Commute = commute
+}
+
+// Required the least education needed to work in a type of building
+func Required(buildingType commonModels.BuildingType) commonModels.EducationLevel {
+	switch buildingType {
+	case commonModels.School, commonModels.Hospital:
+		return commonModels.College
+	case commonModels.Office, commonModels.Police:
+		return commonModels.Secondary
+	}
+	return commonModels.Primary
+}
+
+// Qualified whether a person has the education to work in a type of building
+func Qualified(person commonModels.Person, buildingType commonModels.BuildingType) bool {
+	return person.Education >= Required(buildingType)
+}
+
+// Name of an education level, for logs
+func Name(level commonModels.EducationLevel) string {
+	switch level {
+	case commonModels.Primary:
+		return "primary school"
+	case commonModels.Secondary:
+		return "secondary school"
+	case commonModels.College:
+		return "college"
+	}
+	return "no school"
+}
@@ -0,0 +1,150 @@
+package geometry
+
+import (
+	"errors"
+	. "image"
+	"math"
+	"math/rand"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// FEETPERMILE world coordinates are in feet
+	FEETPERMILE = 5280
+	// SECONDSPERHOUR for converting speeds
+	SECONDSPERHOUR = 3600
+	// WORLDSIZE width and height of a world when the settings don't say, 1000
+	// miles
+	WORLDSIZE = 1000 * FEETPERMILE
+	// CITYSIZE width and height of a new city when the settings don't say, 1
+	// mile
+	CITYSIZE = FEETPERMILE
+	// FOOTPRINT width and depth of a building
+	FOOTPRINT = 208
+	// PLACEATTEMPTS random spots tried for a new city before giving up
+	PLACEATTEMPTS = 100
+)
+
+var (
+	// ErrOutsideWorld a city doesn't fit inside the world bounds
+	ErrOutsideWorld = errors.New("city is outside the world")
+	// ErrCityOverlap a city overlaps an existing one
+	ErrCityOverlap = errors.New("city overlaps an existing city")
+	// ErrUnbuildable the land under a city can't be built on
+	ErrUnbuildable = errors.New("city is on land that can't be built on")
+)
+
+// Distance straight line distance in feet
+func Distance(a Point, b Point) float64 {
+	return math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+}
+
+// Miles feet in miles
+func Miles(feet float64) float64 {
+	return feet / FEETPERMILE
+}
+
+// Feet miles in feet, rounded to the nearest foot
+func Feet(miles float64) int {
+	return int(math.Round(miles * FEETPERMILE))
+}
+
+// FeetPerSecond mph in feet per second
+func FeetPerSecond(mph float64) float64 {
+	return mph * FEETPERMILE / SECONDSPERHOUR
+}
+
+// Bounds the rectangle from topLeft up to but not including bottomRight, the
+// way cities and buildings store their extent
+func Bounds(topLeft Point, bottomRight Point) Rectangle {
+	return Rectangle{Min: topLeft, Max: bottomRight}
+}
+
+// Center the middle of a rectangle
+func Center(r Rectangle) Point {
+	return Point{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Contains whether p is inside r
+func Contains(r Rectangle, p Point) bool {
+	return p.In(r)
+}
+
+// Nearest order buildings by distance from location, nearest first. Ties keep
+// the order they were given in.
+func Nearest(buildings []commonModels.Building, location Point) []commonModels.Building {
+	sorted := append([]commonModels.Building{}, buildings...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && Distance(sorted[j].TopLeft, location) < Distance(sorted[j-1].TopLeft, location); j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	return sorted
+}
+
+// World the bounds of the world, the default size for settings saved before
+// worlds had bounds
+func World(settings commonModels.Settings) Rectangle {
+	world := Bounds(settings.WorldTopLeft, settings.WorldBottomRight)
+	if world.Empty() {
+		return Rect(0, 0, WORLDSIZE, WORLDSIZE)
+	}
+	return world
+}
+
+// CitySize width and height of new cities
+func CitySize(settings commonModels.Settings) int {
+	if settings.CitySize <= 0 {
+		return CITYSIZE
+	}
+	return settings.CitySize
+}
+
+// City the bounds of a city
+func City(city commonModels.City) Rectangle {
+	return Bounds(city.TopLeft, city.BottomRight)
+}
+
+// Building the land a building stands on
+func Building(building commonModels.Building) Rectangle {
+	return Bounds(building.TopLeft, building.BottomRight)
+}
+
+// ValidateCity check that a city fits inside the world without overlapping
+// any of the others
+func ValidateCity(settings commonModels.Settings, city Rectangle, cities []commonModels.City) error {
+	if city.Empty() || !city.In(World(settings)) {
+		return ErrOutsideWorld
+	}
+	for _, other := range cities {
+		if city.Overlaps(City(other)) {
+			return ErrCityOverlap
+		}
+	}
+	return nil
+}
+
+// PlaceCity a random spot in the world for a new city that doesn't overlap
+// the others, on land that buildable accepts if it isn't nil
+func PlaceCity(rng *rand.Rand, settings commonModels.Settings, cities []commonModels.City, buildable func(Rectangle) bool) (Rectangle, error) {
+	world := World(settings)
+	size := CitySize(settings)
+	if world.Dx() < size || world.Dy() < size {
+		return Rectangle{}, ErrOutsideWorld
+	}
+	err := ErrCityOverlap
+	for i := 0; i < PLACEATTEMPTS; i++ {
+		topLeft := Point{X: world.Min.X + rng.Intn(world.Dx()-size+1), Y: world.Min.Y + rng.Intn(world.Dy()-size+1)}
+		city := Rectangle{Min: topLeft, Max: topLeft.Add(Point{X: size, Y: size})}
+		if err = ValidateCity(settings, city, cities); err != nil {
+			continue
+		}
+		if buildable != nil && !buildable(city) {
+			err = ErrUnbuildable
+			continue
+		}
+		return city, nil
+	}
+	return Rectangle{}, err
+}
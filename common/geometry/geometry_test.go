@@ -0,0 +1,71 @@
+package geometry
+
+import (
+	. "image"
+	"testing"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+)
+
+// small a ten mile world with one mile cities
+var small = commonModels.Settings{WorldBottomRight: Point{X: 10 * FEETPERMILE, Y: 10 * FEETPERMILE}, CitySize: FEETPERMILE}
+
+func TestValidateCity(t *testing.T) {
+	existing := []commonModels.City{{TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: FEETPERMILE, Y: FEETPERMILE}}}
+	tests := []struct {
+		name string
+		city Rectangle
+		want error
+	}{
+		{"free", Rect(2*FEETPERMILE, 0, 3*FEETPERMILE, FEETPERMILE), nil},
+		{"sharing an edge", Rect(FEETPERMILE, 0, 2*FEETPERMILE, FEETPERMILE), nil},
+		{"overlapping", Rect(FEETPERMILE-1, 0, 2*FEETPERMILE, FEETPERMILE), ErrCityOverlap},
+		{"past the edge", Rect(9*FEETPERMILE+1, 0, 10*FEETPERMILE+1, FEETPERMILE), ErrOutsideWorld},
+		{"before the edge", Rect(-1, 2*FEETPERMILE, FEETPERMILE-1, 3*FEETPERMILE), ErrOutsideWorld},
+		{"empty", Rectangle{}, ErrOutsideWorld},
+	}
+	for _, tt := range tests {
+		if got := ValidateCity(small, tt.city, existing); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceCity(t *testing.T) {
+	cities := []commonModels.City{}
+	for i := 0; i < 20; i++ {
+		city, err := PlaceCity(random.New(1, "place", string(rune('a'+i))), small, cities, nil)
+		if err != nil {
+			t.Fatalf("city %d: %s", i, err)
+		}
+		again, _ := PlaceCity(random.New(1, "place", string(rune('a'+i))), small, cities, nil)
+		if again != city {
+			t.Errorf("city %d: the same seed placed it at %v then %v", i, city, again)
+		}
+		if !city.In(World(small)) || city.Dx() != FEETPERMILE || city.Dy() != FEETPERMILE {
+			t.Errorf("city %d: %v isn't a one mile city inside the world", i, city)
+		}
+		for _, other := range cities {
+			if city.Overlaps(City(other)) {
+				t.Errorf("city %d: %v overlaps %v", i, city, City(other))
+			}
+		}
+		cities = append(cities, commonModels.City{TopLeft: city.Min, BottomRight: city.Max})
+	}
+}
+
+func TestPlaceCityFails(t *testing.T) {
+	rng := random.New(1, "place")
+	tiny := commonModels.Settings{WorldBottomRight: Point{X: 100, Y: 100}, CitySize: FEETPERMILE}
+	if _, err := PlaceCity(rng, tiny, nil, nil); err != ErrOutsideWorld {
+		t.Errorf("city bigger than the world: got %v", err)
+	}
+	if _, err := PlaceCity(rng, small, nil, func(Rectangle) bool { return false }); err != ErrUnbuildable {
+		t.Errorf("nowhere buildable: got %v", err)
+	}
+	full := []commonModels.City{{TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: 10 * FEETPERMILE, Y: 10 * FEETPERMILE}}}
+	if _, err := PlaceCity(rng, small, full, nil); err != ErrCityOverlap {
+		t.Errorf("world full: got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package healthcare
+
+import (
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/disease"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// BEDSPERFLOOR patient beds on each floor of a hospital
+	BEDSPERFLOOR = 20
+	// ADMITHEALTH people below this health need a hospital
+	ADMITHEALTH = 50
+	// ADMITSEVERITY detected illnesses at least this severe need a hospital
+	ADMITSEVERITY = 0.2
+	// DISCHARGEHEALTH patients are sent home once well and above this health
+	DISCHARGEHEALTH = 80
+	// TREATEDLETHALITY share of a disease's lethality left with proper care
+	TREATEDLETHALITY = 0.5
+	// DAILYRECOVERY health regained each day in a hospital bed
+	DAILYRECOVERY = 10
+)
+
+// Hospitals hospitals in the list that are open at t
+func Hospitals(buildings []commonModels.Building, t time.Time) []commonModels.Building {
+	hospitals := []commonModels.Building{}
+	for _, b := range buildings {
+		if b.Type == commonModels.Hospital && !b.BuildDate.After(t) {
+			hospitals = append(hospitals, b)
+		}
+	}
+	return hospitals
+}
+
+// Beds patient capacity of a hospital, its floors fill up until the building
+// is at its maximum occupancy
+func Beds(hospital commonModels.Building) int {
+	beds := hospital.Floors * BEDSPERFLOOR
+	if hospital.MaxOccupancy > 0 && beds > hospital.MaxOccupancy {
+		beds = hospital.MaxOccupancy
+	}
+	return beds
+}
+
+// NeedsCare whether someone is hurt or sick enough for a hospital
+func NeedsCare(settings commonModels.Settings, person commonModels.Person, t time.Time) bool {
+	if !demographics.Alive(person) {
+		return false
+	}
+	if person.Health < ADMITHEALTH {
+		return true
+	}
+	if !disease.Detected(settings, person, t) {
+		return false
+	}
+	d, ok := disease.Find(settings, person.Illness)
+	return ok && d.Severity >= ADMITSEVERITY
+}
+
+// Discharged whether a patient is well enough to go home
+func Discharged(person commonModels.Person) bool {
+	return !disease.Ill(person) && person.Health >= DISCHARGEHEALTH
+}
+
+// Lethality scale on disease lethality for a patient, 1 for the untreated.
+// Care gets worse as patients outnumber beds until it's no better than none.
+func Lethality(beds int, patients int) float64 {
+	if beds <= 0 {
+		return 1
+	}
+	if patients <= beds {
+		return TREATEDLETHALITY
+	}
+	scale := TREATEDLETHALITY * float64(patients) / float64(beds)
+	if scale > 1 {
+		return 1
+	}
+	return scale
+}
+
+// Admit check a person into a hospital
+func Admit(person *commonModels.Person, hospital commonModels.Building) {
+	person.Hospital = hospital.ID
+}
+
+// Discharge send a patient home
+func Discharge(person *commonModels.Person) {
+	person.Hospital = ""
+}
+
+// Treat a day of bed rest
+func Treat(person *commonModels.Person) {
+	person.Health += DAILYRECOVERY
+	if person.Health > 100 {
+		person.Health = 100
+	}
+}
+
+// Occupancy beds and patients of a hospital, for reports
+type Occupancy struct {
+	Hospital commonModels.Building
+	Beds     int
+	Patients int
+}
+
+// Overloaded whether there are more patients than beds
+func (o Occupancy) Overloaded() bool {
+	return o.Patients > o.Beds
+}
@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	. "image"
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/economy"
+	"github.com/toasterlint/DAWS/common/education"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// QUITRATE yearly chance a happy worker quits, unhappy workers quit up to
+	// twice as often
+	QUITRATE = 0.1
+	// FIRERATE yearly chance a worker is fired
+	FIRERATE = 0.03
+	// OVERQUALIFIED how much further away a job seems for each level of
+	// education more than it needs
+	OVERQUALIFIED = 0.5
+)
+
+// Slots number of jobs a building offers, 0 for homes
+func Slots(building commonModels.Building) int {
+	perFloor := 0
+	switch building.Type {
+	case commonModels.Office:
+		perFloor = 25
+	case commonModels.Warehouse:
+		perFloor = 15
+	case commonModels.Hospital:
+		perFloor = 15
+	case commonModels.Retail, commonModels.School, commonModels.Police, commonModels.Entertainment:
+		perFloor = 10
+	}
+	slots := building.Floors * perFloor
+	if building.MaxOccupancy > 0 && slots > building.MaxOccupancy {
+		slots = building.MaxOccupancy
+	}
+	return slots
+}
+
+// Vacancy open jobs at a building
+type Vacancy struct {
+	Building commonModels.Building
+	Open     int
+}
+
+// Vacancies open jobs at the buildings in the list that are open at t, given
+// how many people already work at each
+func Vacancies(buildings []commonModels.Building, t time.Time, employees func(commonModels.Building) (int, error)) ([]Vacancy, error) {
+	vacancies := []Vacancy{}
+	for _, b := range buildings {
+		slots := Slots(b)
+		if slots == 0 || b.BuildDate.After(t) {
+			continue
+		}
+		n, err := employees(b)
+		if err != nil {
+			return vacancies, err
+		}
+		if n < slots {
+			vacancies = append(vacancies, Vacancy{Building: b, Open: slots - n})
+		}
+	}
+	return vacancies, nil
+}
+
+// Workforce whether a person is of working age, alive and out of school
+func Workforce(person commonModels.Person, t time.Time) bool {
+	return demographics.Alive(person) && demographics.WorkingAge(person, t) && !person.School.Valid()
+}
+
+// Employed whether a person has a job
+func Employed(person commonModels.Person) bool {
+	return person.WorkBuilding.Valid() && person.WorkBuilding != person.HomeBuilding
+}
+
+// Match the index of the best vacancy for a person living at home, the
+// nearest they're qualified for with jobs that need less education than they
+// have counted as further away. -1 if there's nothing they can do.
+func Match(person commonModels.Person, vacancies []Vacancy, home Point) int {
+	best, bestScore := -1, 0.0
+	for i, v := range vacancies {
+		if v.Open <= 0 || !education.Qualified(person, v.Building.Type) {
+			continue
+		}
+		over := float64(person.Education - education.Required(v.Building.Type))
+		score := geometry.Distance(home, v.Building.TopLeft) * (1 + OVERQUALIFIED*over)
+		if best < 0 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// Hire give a person the job at the vacancy
+func Hire(person *commonModels.Person, vacancy *Vacancy) {
+	person.WorkBuilding = vacancy.Building.ID
+	person.Wage = economy.Wage(vacancy.Building.Type, person.Education)
+	vacancy.Open--
+}
+
+// Leave take a person out of their job
+func Leave(person *commonModels.Person) {
+	person.WorkBuilding = ""
+	person.Wage = 0
+}
+
+// Quits roll whether a worker quits today, more likely when they're unhappy
+func Quits(rng *rand.Rand, person commonModels.Person) bool {
+	unhappiness := float64(100-person.Happiness) / 100
+	if unhappiness < 0 {
+		unhappiness = 0
+	}
+	return rng.Float64() < demographics.Daily(QUITRATE*(1+unhappiness))
+}
+
+// Fired roll whether a worker is fired today
+func Fired(rng *rand.Rand) bool {
+	return rng.Float64() < demographics.Daily(FIRERATE)
+}
+
+// Stat the labor figures of a city's residents at t
+func Stat(cityID bson.ObjectId, residents []commonModels.Person, t time.Time) commonModels.CityStat {
+	stat := commonModels.CityStat{CityID: cityID, Day: t, Population: len(residents)}
+	for _, p := range residents {
+		if !Workforce(p, t) {
+			continue
+		}
+		stat.LaborForce++
+		if Employed(p) {
+			stat.Employed++
+		} else {
+			stat.Unemployed++
+		}
+	}
+	return stat
+}
+
+// UnemploymentRate share of the labor force out of work
+func UnemploymentRate(stat commonModels.CityStat) float64 {
+	if stat.LaborForce == 0 {
+		return 0
+	}
+	return float64(stat.Unemployed) / float64(stat.LaborForce)
+}
@@ -0,0 +1,283 @@
+package maptile
+
+import (
+	. "image"
+	"image/color"
+	"image/draw"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/terrain"
+)
+
+const (
+	// PIXELS width and height of a tile image
+	PIXELS = 256
+	// MAXZOOM deepest zoom level served in the largest worlds, buildings are a
+	// few pixels across
+	MAXZOOM = 14
+	// STREETPIXELS city width in pixels from which its streets are drawn
+	STREETPIXELS = 64
+)
+
+var (
+	cityColor     = color.RGBA{R: 60, G: 60, B: 60, A: 255}
+	streetColor   = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	highwayColor  = color.RGBA{R: 230, G: 140, B: 40, A: 255}
+	travelerColor = color.RGBA{R: 220, G: 20, B: 60, A: 255}
+)
+
+// buildingColors fill of each type of building
+var buildingColors = map[commonModels.BuildingType]color.RGBA{
+	commonModels.House:         {R: 205, G: 133, B: 63, A: 255},
+	commonModels.Apartment:     {R: 160, G: 82, B: 45, A: 255},
+	commonModels.School:        {R: 255, G: 215, B: 0, A: 255},
+	commonModels.Office:        {R: 70, G: 130, B: 180, A: 255},
+	commonModels.Warehouse:     {R: 112, G: 128, B: 144, A: 255},
+	commonModels.Retail:        {R: 218, G: 112, B: 214, A: 255},
+	commonModels.Entertainment: {R: 255, G: 105, B: 180, A: 255},
+	commonModels.Hospital:      {R: 255, G: 255, B: 255, A: 255},
+	commonModels.Police:        {R: 25, G: 25, B: 112, A: 255},
+}
+
+// Layers what is drawn over the terrain
+type Layers struct {
+	Cities    []commonModels.City
+	Highways  []commonModels.HighwayRoute
+	Buildings []commonModels.Building
+	Travelers []Point
+}
+
+// MaxZoom deepest zoom level served for a world, no deeper than MAXZOOM and
+// never so deep that a pixel is less than a foot across
+func MaxZoom(settings commonModels.Settings) int {
+	world := geometry.World(settings)
+	z := 0
+	for z < MAXZOOM && world.Dx()>>uint(z+1) >= PIXELS && world.Dy()>>uint(z+1) >= PIXELS {
+		z++
+	}
+	return z
+}
+
+// Bounds the part of the world a tile shows. At zoom z the world is split
+// into 2^z by 2^z tiles. False if there's no such tile.
+func Bounds(settings commonModels.Settings, z int, x int, y int) (Rectangle, bool) {
+	if z < 0 || z > MaxZoom(settings) {
+		return Rectangle{}, false
+	}
+	n := 1 << uint(z)
+	if x < 0 || y < 0 || x >= n || y >= n {
+		return Rectangle{}, false
+	}
+	world := geometry.World(settings)
+	bounds := Rect(
+		world.Min.X+world.Dx()*x/n, world.Min.Y+world.Dy()*y/n,
+		world.Min.X+world.Dx()*(x+1)/n, world.Min.Y+world.Dy()*(y+1)/n,
+	)
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return Rectangle{}, false
+	}
+	return bounds, true
+}
+
+// tile maps world feet to the pixels of a tile
+type tile struct {
+	img    *RGBA
+	bounds Rectangle
+}
+
+func (t tile) pixel(p Point) Point {
+	return Point{
+		X: (p.X - t.bounds.Min.X) * PIXELS / t.bounds.Dx(),
+		Y: (p.Y - t.bounds.Min.Y) * PIXELS / t.bounds.Dy(),
+	}
+}
+
+func (t tile) world(px int, py int) Point {
+	return Point{
+		X: t.bounds.Min.X + (2*px+1)*t.bounds.Dx()/(2*PIXELS),
+		Y: t.bounds.Min.Y + (2*py+1)*t.bounds.Dy()/(2*PIXELS),
+	}
+}
+
+// Render draw the tile showing bounds, terrain first with cities, highways,
+// buildings and travelers on top. Terrain is computed from the seed rather
+// than loaded, so zoomed out tiles don't touch every stored terrain tile.
+func Render(land *terrain.Map, bounds Rectangle, layers Layers) *RGBA {
+	t := tile{img: NewRGBA(Rect(0, 0, PIXELS, PIXELS)), bounds: bounds}
+	for py := 0; py < PIXELS; py++ {
+		for px := 0; px < PIXELS; px++ {
+			t.img.SetRGBA(px, py, terrainColor(terrain.At(land.Seed(), t.world(px, py))))
+		}
+	}
+	for _, highway := range layers.Highways {
+		for _, road := range highway.Roads {
+			t.line(road.From, road.To, highwayColor)
+		}
+	}
+	for _, city := range layers.Cities {
+		r := geometry.City(city)
+		if !r.Overlaps(bounds) {
+			continue
+		}
+		if r.Dx()*PIXELS/bounds.Dx() >= STREETPIXELS {
+			for _, road := range city.Roads {
+				t.line(road.From, road.To, streetColor)
+			}
+		}
+		t.outline(r, cityColor)
+	}
+	for _, b := range layers.Buildings {
+		c, ok := buildingColors[b.Type]
+		if !ok {
+			c = cityColor
+		}
+		t.fill(geometry.Building(b), c)
+	}
+	for _, p := range layers.Travelers {
+		if p.In(bounds) {
+			px := t.pixel(p)
+			draw.Draw(t.img, Rect(px.X-1, px.Y-1, px.X+2, px.Y+2), &Uniform{C: travelerColor}, Point{}, draw.Src)
+		}
+	}
+	return t.img
+}
+
+// terrainColor shade of a kind of land, darker for deeper water and lighter
+// for higher ground
+func terrainColor(cover commonModels.LandCover, elevation int) color.RGBA {
+	shade := func(c color.RGBA, amount int) color.RGBA {
+		adjust := func(v uint8) uint8 {
+			n := int(v) + amount
+			if n < 0 {
+				return 0
+			}
+			if n > 255 {
+				return 255
+			}
+			return uint8(n)
+		}
+		return color.RGBA{R: adjust(c.R), G: adjust(c.G), B: adjust(c.B), A: 255}
+	}
+	lift := elevation * 40 / terrain.PEAK
+	switch cover {
+	case commonModels.Water:
+		return shade(color.RGBA{R: 70, G: 130, B: 200, A: 255}, lift)
+	case commonModels.Farmland:
+		return shade(color.RGBA{R: 210, G: 200, B: 120, A: 255}, lift)
+	case commonModels.Forest:
+		return shade(color.RGBA{R: 40, G: 110, B: 50, A: 255}, lift)
+	case commonModels.Mountains:
+		return shade(color.RGBA{R: 140, G: 130, B: 120, A: 255}, lift*2)
+	}
+	return shade(color.RGBA{R: 150, G: 190, B: 110, A: 255}, lift)
+}
+
+// fill a world rectangle, at least a pixel so small buildings still show
+func (t tile) fill(r Rectangle, c color.RGBA) {
+	if !r.Overlaps(t.bounds) {
+		return
+	}
+	min, max := t.pixel(r.Min), t.pixel(r.Max)
+	if max.X <= min.X {
+		max.X = min.X + 1
+	}
+	if max.Y <= min.Y {
+		max.Y = min.Y + 1
+	}
+	draw.Draw(t.img, Rectangle{Min: min, Max: max}, &Uniform{C: c}, Point{}, draw.Src)
+}
+
+// outline the edges of a world rectangle
+func (t tile) outline(r Rectangle, c color.RGBA) {
+	corners := []Point{r.Min, {X: r.Max.X, Y: r.Min.Y}, r.Max, {X: r.Min.X, Y: r.Max.Y}}
+	for i := range corners {
+		t.line(corners[i], corners[(i+1)%len(corners)], c)
+	}
+}
+
+// line a one pixel line between two world points, clipped to the tile
+func (t tile) line(from Point, to Point, c color.RGBA) {
+	from, to, ok := clip(from, to, t.bounds)
+	if !ok {
+		return
+	}
+	a, b := t.pixel(from), t.pixel(to)
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	err := dx + dy
+	for {
+		if a.X >= 0 && a.Y >= 0 && a.X < PIXELS && a.Y < PIXELS {
+			t.img.SetRGBA(a.X, a.Y, c)
+		}
+		if a == b {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			a.Y += sy
+		}
+	}
+}
+
+// clip the part of a segment inside r, Liang-Barsky style
+func clip(from Point, to Point, r Rectangle) (Point, Point, bool) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	t0, t1 := 0.0, 1.0
+	edges := []struct{ p, q float64 }{
+		{-dx, float64(from.X - r.Min.X)},
+		{dx, float64(r.Max.X - from.X)},
+		{-dy, float64(from.Y - r.Min.Y)},
+		{dy, float64(r.Max.Y - from.Y)},
+	}
+	for _, e := range edges {
+		if e.p == 0 {
+			if e.q < 0 {
+				return from, to, false
+			}
+			continue
+		}
+		t := e.q / e.p
+		if e.p < 0 {
+			if t > t1 {
+				return from, to, false
+			}
+			if t > t0 {
+				t0 = t
+			}
+		} else {
+			if t < t0 {
+				return from, to, false
+			}
+			if t < t1 {
+				t1 = t
+			}
+		}
+	}
+	at := func(t float64) Point {
+		return Point{X: from.X + int(t*dx), Y: from.Y + int(t*dy)}
+	}
+	return at(t0), at(t1), true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package maptile
+
+import (
+	. "image"
+	"testing"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/terrain"
+)
+
+func world(feet int) commonModels.Settings {
+	return commonModels.Settings{Seed: 1, WorldBottomRight: Point{X: feet, Y: feet}}
+}
+
+func TestMaxZoom(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings commonModels.Settings
+		want     int
+	}{
+		{"default world", commonModels.Settings{}, MAXZOOM},
+		{"ten miles", world(10 * geometry.FEETPERMILE), 7},
+		{"exactly one tile of feet", world(PIXELS), 0},
+		{"two tiles of feet", world(2 * PIXELS), 1},
+		{"smaller than a tile", world(100), 0},
+	}
+	for _, tt := range tests {
+		if got := MaxZoom(tt.settings); got != tt.want {
+			t.Errorf("%s: MaxZoom %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	settings := world(10 * geometry.FEETPERMILE)
+	deepest := MaxZoom(settings)
+	tests := []struct {
+		name    string
+		z, x, y int
+		ok      bool
+	}{
+		{"whole world", 0, 0, 0, true},
+		{"negative zoom", -1, 0, 0, false},
+		{"deepest zoom", deepest, 0, 0, true},
+		{"past the deepest zoom", deepest + 1, 0, 0, false},
+		{"last tile", 3, 7, 7, true},
+		{"past the last tile", 3, 8, 0, false},
+		{"negative tile", 3, 0, -1, false},
+	}
+	for _, tt := range tests {
+		bounds, ok := Bounds(settings, tt.z, tt.x, tt.y)
+		if ok != tt.ok {
+			t.Errorf("%s: ok %v, want %v", tt.name, ok, tt.ok)
+		}
+		if ok && (bounds.Empty() || !bounds.In(geometry.World(settings))) {
+			t.Errorf("%s: bounds %v aren't a part of the world", tt.name, bounds)
+		}
+	}
+
+	// the tiles at a zoom cover the world edge to edge without gaps
+	for z := 0; z <= deepest; z++ {
+		n := 1 << uint(z)
+		for x := 0; x < n; x++ {
+			a, _ := Bounds(settings, z, x, 0)
+			if x == 0 && a.Min.X != 0 {
+				t.Errorf("z %d: first tile starts at %d", z, a.Min.X)
+			}
+			if x == n-1 && a.Max.X != geometry.World(settings).Max.X {
+				t.Errorf("z %d: last tile ends at %d", z, a.Max.X)
+			}
+			if b, ok := Bounds(settings, z, x+1, 0); ok && b.Min.X != a.Max.X {
+				t.Errorf("z %d: gap between tiles %d and %d", z, x, x+1)
+			}
+		}
+	}
+}
+
+func TestRenderTinyWorld(t *testing.T) {
+	settings := world(100)
+	bounds, ok := Bounds(settings, 0, 0, 0)
+	if !ok {
+		t.Fatal("expected a tile for a tiny world")
+	}
+	img := Render(terrain.NewMap(settings, nil), bounds, Layers{Travelers: []Point{{X: 50, Y: 50}}})
+	if img.Bounds() != Rect(0, 0, PIXELS, PIXELS) {
+		t.Errorf("rendered %v, want %dpx square", img.Bounds(), PIXELS)
+	}
+	if _, ok := Bounds(settings, 1, 0, 0); ok {
+		t.Errorf("expected no zoom into a world smaller than a tile")
+	}
+}
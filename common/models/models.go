@@ -30,6 +30,14 @@ type Settings struct {
 	LastTime                time.Time     `json:"lastTime" bson:"lastTime"`
 	Triggers                []Trigger     `json:"triggers" bson:"triggers"`
 	SpeedLimits             []SpeedLimit  `json:"speedLimits" bson:"speedLimits"`
+	NameDataset             string        `json:"nameDataset" bson:"nameDataset"`
+	Seed                    int64         `json:"seed" bson:"seed"`
+	Tick                    int64         `json:"tick" bson:"tick"`
+	// WorldTopLeft and WorldBottomRight bound where cities can be placed,
+	// CitySize is the width and height of new cities, all in feet
+	WorldTopLeft     Point `json:"worldTopLeft" bson:"worldTopLeft"`
+	WorldBottomRight Point `json:"worldBottomRight" bson:"worldBottomRight"`
+	CitySize         int   `json:"citySize" bson:"citySize"`
 }
 
 // WorldQueueMessage Messages sent to World Queue
@@ -50,14 +58,20 @@ type WorldCityQueueMessage struct {
 	City          string   `json:"city"`
 }
 
+// CityWorkerQueueMessage a building to simulate, ReplyTo is the queue to
+// report back on once it's done
 type CityWorkerQueueMessage struct {
 	WorldSettings Settings      `json:"worldSettings"`
 	BuildingID    bson.ObjectId `json:"buildingid"`
+	ReplyTo       string        `json:"replyTo"`
 }
 
+// TrafficWorkerQueueMessage a traveler to move, ReplyTo is the queue to
+// report back on once it's done
 type TrafficWorkerQueueMessage struct {
 	WorldSettings Settings      `json:"worldSettings"`
 	PersonID      bson.ObjectId `json:"personid"`
+	ReplyTo       string        `json:"replyTo"`
 }
 
 type ControllerType int
@@ -86,6 +100,59 @@ type City struct {
 	TopLeft     Point         `json:"topleft" bson:"topleft"`
 	BottomRight Point         `json:"bottomright" bson:"bottomright"`
 	Established time.Time     `json:"established" bson:"established"`
+	Roads       []Road        `json:"roads" bson:"roads"`
+	Balance     int           `json:"balance" bson:"balance"`
+}
+
+// RoadClass used to identify the type of road, which sets its speed limit
+type RoadClass int
+
+const (
+	Street RoadClass = iota + 1
+	Highway
+)
+
+// Road a straight stretch of road between two intersections
+type Road struct {
+	From  Point     `json:"from" bson:"from"`
+	To    Point     `json:"to" bson:"to"`
+	Class RoadClass `json:"class" bson:"class"`
+}
+
+// HighwayRoute highway connecting two cities
+type HighwayRoute struct {
+	ID    bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	From  bson.ObjectId `json:"from" bson:"from"`
+	To    bson.ObjectId `json:"to" bson:"to"`
+	Roads []Road        `json:"roads" bson:"roads"`
+}
+
+// Waypoint a point along a route and the class of road leading to it, 0 for
+// the stretch between a building and the road
+type Waypoint struct {
+	Point Point     `json:"point" bson:"point"`
+	Class RoadClass `json:"class" bson:"class"`
+}
+
+// LandCover what covers the ground
+type LandCover int
+
+const (
+	Water LandCover = iota + 1
+	Plains
+	Farmland
+	Forest
+	Mountains
+)
+
+// TerrainTile a square of the world's terrain at tile X, Y. Cells run row by
+// row from the top left, Elevation is in feet above sea level.
+type TerrainTile struct {
+	ID        bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	X         int           `json:"x" bson:"x"`
+	Y         int           `json:"y" bson:"y"`
+	Elevation []int         `json:"elevation" bson:"elevation"`
+	Cover     []LandCover   `json:"cover" bson:"cover"`
 }
 
 // BuildingType used to identify the type of building
@@ -126,34 +193,148 @@ const (
 	Illness
 )
 
+// Schedule daily routine of a person, times are minutes after midnight in
+// simulated time
+type Schedule struct {
+	Wake      int  `json:"wake" bson:"wake"`
+	WorkStart int  `json:"workstart" bson:"workstart"`
+	WorkEnd   int  `json:"workend" bson:"workend"`
+	Bedtime   int  `json:"bedtime" bson:"bedtime"`
+	Commute   int  `json:"commute" bson:"commute"`
+	Weekends  bool `json:"weekends" bson:"weekends"`
+}
+
+// EducationLevel highest schooling a person has finished
+type EducationLevel int
+
+const (
+	NoEducation EducationLevel = iota
+	Primary
+	Secondary
+	College
+)
+
+// Gender of a person
+type Gender int
+
+const (
+	Male Gender = iota + 1
+	Female
+)
+
 // Person a person
 type Person struct {
 	ID              bson.ObjectId   `json:"id" bson:"_id,omitempty"`
 	Birthdate       time.Time       `json:"birthdate" bson:"birthdate"`
+	Gender          Gender          `json:"gender" bson:"gender"`
 	FirstName       string          `json:"firstname" bson:"firstname"`
 	LastName        string          `json:"lastname" bson:"lastname"`
 	ChildrenIDs     []bson.ObjectId `json:"childrenIDs" bson:"childrenIDs"`
 	CurrentBuilding bson.ObjectId   `json:"currentbuilding" bson:"currentbuilding,omitempty"`
 	CurrentXY       Point           `json:"currentxy" bson:"currentxy"`
 	Traveling       bool            `json:"traveling" bson:"traveling"`
+	Destination     Point           `json:"destination" bson:"destination"`
+	DestinationID   bson.ObjectId   `json:"destinationid" bson:"destinationid,omitempty"`
+	Route           []Waypoint      `json:"route" bson:"route"`
 	NewToBuilding   bool            `json:"newtobuilding" bson:"newtobuilding"`
 	HomeBuilding    bson.ObjectId   `json:"homebuilding" bson:"homebuilding,omitempty"`
 	WorkBuilding    bson.ObjectId   `json:"workbuilding" bson:"workbuilding,omitempty"`
+	School          bson.ObjectId   `json:"school" bson:"school,omitempty"`
+	Education       EducationLevel  `json:"education" bson:"education"`
+	Wage            int             `json:"wage" bson:"wage"`
+	Balance         int             `json:"balance" bson:"balance"`
+	Schedule        Schedule        `json:"schedule" bson:"schedule"`
 	Health          int             `json:"health" bson:"health"`
 	Illness         bson.ObjectId   `json:"illness" bson:"illness,omitempty"`
+	IllSince        time.Time       `json:"illsince" bson:"illsince"`
+	IllUntil        time.Time       `json:"illuntil" bson:"illuntil"`
+	Immunities      []bson.ObjectId `json:"immunities" bson:"immunities"`
+	Hospital        bson.ObjectId   `json:"hospital" bson:"hospital,omitempty"`
 	Happiness       int             `json:"happiness" bson:"happiness"`
 	DeathDate       time.Time       `json:"deathdate" bson:"deathdate"`
 	CauseOfDeath    DeathType       `json:"causeofdeath" bson:"causeofdeath"`
-	Spouse          bson.ObjectId   `json:"spouse" bson:"spouse"`
+	Spouse          bson.ObjectId   `json:"spouse" bson:"spouse,omitempty"`
+	Mother          bson.ObjectId   `json:"mother" bson:"mother,omitempty"`
+	Father          bson.ObjectId   `json:"father" bson:"father,omitempty"`
+	Responding      bson.ObjectId   `json:"responding" bson:"responding,omitempty"`
+	// LastDay the simulated day their daily events were last rolled
+	LastDay time.Time `json:"lastday" bson:"lastday"`
 }
 
-// Disease types of diseases
+// Disease types of diseases. InfectionChance is per hour spent in the same
+// building as someone infectious, OnsetRate is the yearly chance of catching
+// it from outside the world, Severity is the share of 10 health lost per day.
 type Disease struct {
-	Name            string  `json:"name" bson:"name"`
-	DaysDetected    int     `json:"daysDetected" bson:"daysDetected"`
-	AvgDaysIll      int     `json:"avgDaysIll" bson:"avgDaysIll"`
-	LethalityRate   float32 `json:"lethalityRate" bson:"lethalityRate"`
-	Infectious      bool    `json:"infectious" bson:"infectious"`
-	InfectionChance float32 `json:"infectionChance" bson:"infectionChance"`
-	Severity        float32 `json:"severity" bson:"severity"`
+	ID              bson.ObjectId `json:"id" bson:"id,omitempty"`
+	Name            string        `json:"name" bson:"name"`
+	DaysDetected    int           `json:"daysDetected" bson:"daysDetected"`
+	AvgDaysIll      int           `json:"avgDaysIll" bson:"avgDaysIll"`
+	LethalityRate   float32       `json:"lethalityRate" bson:"lethalityRate"`
+	Infectious      bool          `json:"infectious" bson:"infectious"`
+	InfectionChance float32       `json:"infectionChance" bson:"infectionChance"`
+	Severity        float32       `json:"severity" bson:"severity"`
+	OnsetRate       float32       `json:"onsetRate" bson:"onsetRate"`
+}
+
+// DiseaseStat case counts of a disease for one simulated day
+type DiseaseStat struct {
+	ID         bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	DiseaseID  bson.ObjectId `json:"diseaseid" bson:"diseaseid"`
+	Day        time.Time     `json:"day" bson:"day"`
+	NewCases   int           `json:"newcases" bson:"newcases"`
+	Recoveries int           `json:"recoveries" bson:"recoveries"`
+	Deaths     int           `json:"deaths" bson:"deaths"`
+}
+
+// CityStat a city's figures for one simulated day. The labor force is the
+// living adults of working age who aren't in school.
+type CityStat struct {
+	ID         bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	CityID     bson.ObjectId `json:"cityid" bson:"cityid"`
+	Day        time.Time     `json:"day" bson:"day"`
+	Population int           `json:"population" bson:"population"`
+	LaborForce int           `json:"laborforce" bson:"laborforce"`
+	Employed   int           `json:"employed" bson:"employed"`
+	Unemployed int           `json:"unemployed" bson:"unemployed"`
+	// GDP yearly dollars earned by the city's workers
+	GDP          int     `json:"gdp" bson:"gdp"`
+	MedianIncome int     `json:"medianincome" bson:"medianincome"`
+	PovertyRate  float64 `json:"povertyrate" bson:"povertyrate"`
+}
+
+// CrimeType used to identify the kind of crime
+type CrimeType int
+
+const (
+	ViolentCrime CrimeType = iota + 1
+	Homicide
+)
+
+// Crime a crime that happened in a city
+type Crime struct {
+	ID            bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	Type          CrimeType     `json:"type" bson:"type"`
+	Time          time.Time     `json:"time" bson:"time"`
+	CityID        bson.ObjectId `json:"cityid" bson:"cityid"`
+	BuildingID    bson.ObjectId `json:"buildingid" bson:"buildingid,omitempty"`
+	Location      Point         `json:"location" bson:"location"`
+	VictimID      bson.ObjectId `json:"victimid" bson:"victimid"`
+	PerpetratorID bson.ObjectId `json:"perpetratorid" bson:"perpetratorid,omitempty"`
+	StationID     bson.ObjectId `json:"stationid" bson:"stationid,omitempty"`
+	OfficerID     bson.ObjectId `json:"officerid" bson:"officerid,omitempty"`
+	Dispatched    time.Time     `json:"dispatched" bson:"dispatched"`
+	Responded     time.Time     `json:"responded" bson:"responded"`
+	ResponseTime  int           `json:"responsetime" bson:"responsetime"`
+	Arrested      bool          `json:"arrested" bson:"arrested"`
+}
+
+// CarAccident a car accident involving a traveler
+type CarAccident struct {
+	ID       bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	Time     time.Time     `json:"time" bson:"time"`
+	PersonID bson.ObjectId `json:"personid" bson:"personid"`
+	Location Point         `json:"location" bson:"location"`
+	Speed    int           `json:"speed" bson:"speed"`
+	Fatal    bool          `json:"fatal" bson:"fatal"`
+	Injury   int           `json:"injury" bson:"injury"`
 }
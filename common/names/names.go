@@ -0,0 +1,118 @@
+package names
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+// DEFAULTDATASET dataset used when a world doesn't pick one
+const DEFAULTDATASET = "united_states"
+
+//go:embed data
+var data embed.FS
+
+type dataset struct {
+	male     []string
+	female   []string
+	surnames []string
+	cities   []string
+}
+
+var datasets = map[string]*dataset{}
+
+func init() {
+	dirs, err := data.ReadDir("data")
+	if err != nil {
+		panic(err)
+	}
+	for _, dir := range dirs {
+		name := dir.Name()
+		datasets[name] = &dataset{
+			male:     readList(name, "male.txt"),
+			female:   readList(name, "female.txt"),
+			surnames: readList(name, "surnames.txt"),
+			cities:   readList(name, "cities.txt"),
+		}
+	}
+}
+
+func readList(set string, file string) []string {
+	f, err := data.Open(path.Join("data", set, file))
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	seen := map[string]bool{}
+	list := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		list = append(list, line)
+	}
+	if len(list) == 0 {
+		panic(fmt.Sprintf("names: %s/%s is empty", set, file))
+	}
+	return list
+}
+
+// Datasets names of the bundled cultural datasets
+func Datasets() []string {
+	list := []string{}
+	for name := range datasets {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Generator picks names from a dataset, the same rng sequence always gives
+// the same names
+type Generator struct {
+	rng *rand.Rand
+	set *dataset
+}
+
+// NewGenerator create a generator for the dataset, "" uses DEFAULTDATASET
+func NewGenerator(name string, rng *rand.Rand) (*Generator, error) {
+	if name == "" {
+		name = DEFAULTDATASET
+	}
+	set, ok := datasets[name]
+	if !ok {
+		return nil, fmt.Errorf("names: unknown dataset %q", name)
+	}
+	return &Generator{rng: rng, set: set}, nil
+}
+
+func (g *Generator) pick(list []string) string {
+	return list[g.rng.Intn(len(list))]
+}
+
+// FirstName a first name for the gender
+func (g *Generator) FirstName(gender commonModels.Gender) string {
+	if gender == commonModels.Female {
+		return g.pick(g.set.female)
+	}
+	return g.pick(g.set.male)
+}
+
+// Surname a family name
+func (g *Generator) Surname() string {
+	return g.pick(g.set.surnames)
+}
+
+// CityName a city name
+func (g *Generator) CityName() string {
+	return g.pick(g.set.cities)
+}
@@ -0,0 +1,110 @@
+package parcels
+
+import (
+	. "image"
+	"math/rand"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// ROADWIDTH width in feet of the land a road takes up
+	ROADWIDTH = 40
+	// LOTSTEP feet between the rows searched for free lots
+	LOTSTEP = 10
+)
+
+// Land the parcels of a city, which rectangles are taken by buildings and
+// roads. Rectangles run from a building's TopLeft up to but not including its
+// BottomRight, so neighbours can share an edge.
+type Land struct {
+	Bounds Rectangle
+	Taken  []Rectangle
+}
+
+// New the land of a city, with its buildings and the roads of the city and
+// any highways taken
+func New(city commonModels.City, buildings []commonModels.Building, highways []commonModels.HighwayRoute) *Land {
+	land := &Land{Bounds: geometry.City(city)}
+	for _, b := range buildings {
+		if b.CityID == city.ID {
+			land.Take(geometry.Building(b))
+		}
+	}
+	for _, road := range city.Roads {
+		land.takeRoad(road)
+	}
+	for _, highway := range highways {
+		for _, road := range highway.Roads {
+			land.takeRoad(road)
+		}
+	}
+	return land
+}
+
+// Take mark a rectangle as used
+func (l *Land) Take(r Rectangle) {
+	if r.Overlaps(l.Bounds) {
+		l.Taken = append(l.Taken, r.Intersect(l.Bounds))
+	}
+}
+
+// takeRoad mark the strip under a road as used, diagonal roads are covered by
+// squares along their length
+func (l *Land) takeRoad(road commonModels.Road) {
+	half := ROADWIDTH / 2
+	if road.From.X == road.To.X || road.From.Y == road.To.Y {
+		l.Take(Rect(road.From.X, road.From.Y, road.To.X, road.To.Y).Inset(-half))
+		return
+	}
+	length := geometry.Distance(road.From, road.To)
+	steps := int(length/float64(half)) + 1
+	for i := 0; i <= steps; i++ {
+		f := float64(i) / float64(steps)
+		p := Point{X: road.From.X + int(f*float64(road.To.X-road.From.X)), Y: road.From.Y + int(f*float64(road.To.Y-road.From.Y))}
+		l.Take(Rectangle{Min: p, Max: p}.Inset(-half))
+	}
+}
+
+// Lots free lots of width by depth feet that don't overlap each other,
+// searched row by row from the top left of the city
+func (l *Land) Lots(width int, depth int) []Rectangle {
+	lots := []Rectangle{}
+	if width <= 0 || depth <= 0 {
+		return lots
+	}
+	taken := append([]Rectangle{}, l.Taken...)
+	for y := l.Bounds.Min.Y; y+depth <= l.Bounds.Max.Y; y += LOTSTEP {
+		for x := l.Bounds.Min.X; x+width <= l.Bounds.Max.X; {
+			lot := Rect(x, y, x+width, y+depth)
+			blocked := false
+			for _, t := range taken {
+				if lot.Overlaps(t) {
+					// skip past whatever is in the way
+					x = t.Max.X
+					blocked = true
+					break
+				}
+			}
+			if blocked {
+				continue
+			}
+			lots = append(lots, lot)
+			taken = append(taken, lot)
+			x += width
+		}
+	}
+	return lots
+}
+
+// Place pick a random free lot of width by depth feet and take it
+func (l *Land) Place(rng *rand.Rand, width int, depth int) (Rectangle, bool) {
+	lots := l.Lots(width, depth)
+	if len(lots) == 0 {
+		return Rectangle{}, false
+	}
+	lot := lots[rng.Intn(len(lots))]
+	l.Take(lot)
+	return lot, true
+}
@@ -0,0 +1,99 @@
+package parcels
+
+import (
+	. "image"
+	"testing"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+	"github.com/toasterlint/DAWS/common/roads"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// unblocked whether r is inside the land and overlaps none of others
+func unblocked(l *Land, r Rectangle, others []Rectangle) bool {
+	if !r.In(l.Bounds) {
+		return false
+	}
+	for _, o := range others {
+		if r.Overlaps(o) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLotsAvoidBuildingsAndRoads(t *testing.T) {
+	city := commonModels.City{ID: bson.ObjectIdHex("5e5b8f1e2b3c4d5e6f708192"), TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: 2000, Y: 2000}}
+	city.Roads = roads.Grid(city)
+	buildings := []commonModels.Building{
+		{CityID: city.ID, TopLeft: Point{X: 100, Y: 100}, BottomRight: Point{X: 308, Y: 308}},
+		{CityID: city.ID, TopLeft: Point{X: 900, Y: 700}, BottomRight: Point{X: 1108, Y: 908}},
+		// another city's building is ignored
+		{TopLeft: Point{X: 1300, Y: 1300}, BottomRight: Point{X: 1508, Y: 1508}},
+	}
+	highways := []commonModels.HighwayRoute{{Roads: []commonModels.Road{{From: Point{X: 0, Y: 2000}, To: Point{X: 2000, Y: 0}, Class: commonModels.Highway}}}}
+	land := New(city, buildings, highways)
+	taken := append([]Rectangle{}, land.Taken...)
+
+	lots := land.Lots(geometry.FOOTPRINT, geometry.FOOTPRINT)
+	if len(lots) == 0 {
+		t.Fatal("expected free lots in a mostly empty city")
+	}
+	for i, lot := range lots {
+		if lot.Dx() != geometry.FOOTPRINT || lot.Dy() != geometry.FOOTPRINT {
+			t.Errorf("lot %v isn't %d ft square", lot, geometry.FOOTPRINT)
+		}
+		if !unblocked(land, lot, append(taken, lots[:i]...)) {
+			t.Errorf("lot %v overlaps a building, a road or another lot", lot)
+		}
+	}
+	for _, b := range buildings[:2] {
+		if unblocked(land, geometry.Building(b), taken) {
+			t.Errorf("building %v wasn't taken", geometry.Building(b))
+		}
+	}
+}
+
+func TestPlaceUntilFull(t *testing.T) {
+	city := commonModels.City{TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: 1000, Y: 600}}
+	land := New(city, nil, nil)
+	rng := random.New(7, "parcels test")
+	free := len(land.Lots(200, 200))
+	if free != 15 {
+		t.Fatalf("expected 15 lots of 200x200 in 1000x600, got %d", free)
+	}
+	placed := []Rectangle{}
+	for {
+		lot, ok := land.Place(rng, 200, 200)
+		if !ok {
+			break
+		}
+		if !unblocked(land, lot, placed) {
+			t.Fatalf("placed %v over an earlier lot", lot)
+		}
+		placed = append(placed, lot)
+		if len(placed) > free {
+			t.Fatalf("placed more lots than were free")
+		}
+	}
+	if len(land.Lots(200, 200)) != 0 {
+		t.Errorf("expected no lots left once Place gives up")
+	}
+	if len(placed) == 0 {
+		t.Errorf("expected Place to find room in an empty city")
+	}
+}
+
+func TestLotsTooBigOrEmpty(t *testing.T) {
+	land := New(commonModels.City{TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: 500, Y: 500}}, nil, nil)
+	tests := []struct {
+		width, depth int
+	}{{0, 100}, {100, -1}, {501, 100}, {100, 501}}
+	for _, tt := range tests {
+		if lots := land.Lots(tt.width, tt.depth); len(lots) != 0 {
+			t.Errorf("%dx%d: expected no lots, got %v", tt.width, tt.depth, lots)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package police
+
+import (
+	. "image"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// PATROLRADIUS feet around an arrest where it deters crime
+	PATROLRADIUS = 2640
+	// ARRESTMEMORY how long an arrest keeps deterring crime
+	ARRESTMEMORY = 90 * 24 * time.Hour
+	// DETERRENCE how much each nearby arrest cuts crime, 0.25 means four
+	// arrests halve it
+	DETERRENCE = 0.25
+	// ARRESTCHANCE chance of an arrest when officers arrive straight away, it
+	// halves every ARRESTHALFLIFE of response time
+	ARRESTCHANCE = 0.6
+	// ARRESTHALFLIFE response time that halves the chance of an arrest
+	ARRESTHALFLIFE = 10 * time.Minute
+)
+
+// Stations police stations in the list that are open at t
+func Stations(buildings []commonModels.Building, t time.Time) []commonModels.Building {
+	stations := []commonModels.Building{}
+	for _, b := range buildings {
+		if b.Type == commonModels.Police && !b.BuildDate.After(t) {
+			stations = append(stations, b)
+		}
+	}
+	return stations
+}
+
+// Available indexes of the officers at the station who are free to respond
+func Available(station commonModels.Building, occupants []commonModels.Person) []int {
+	officers := []int{}
+	for i := range occupants {
+		p := occupants[i]
+		if p.WorkBuilding == station.ID && p.CurrentBuilding == station.ID && !p.Traveling && !p.Responding.Valid() && demographics.Alive(p) {
+			officers = append(officers, i)
+		}
+	}
+	return officers
+}
+
+// Dispatch send an officer from the station to the crime
+func Dispatch(officer *commonModels.Person, crime *commonModels.Crime, station commonModels.Building, t time.Time) {
+	crime.StationID = station.ID
+	crime.OfficerID = officer.ID
+	crime.Dispatched = t
+	officer.Responding = crime.ID
+	officer.Traveling = true
+	officer.NewToBuilding = false
+	officer.Destination = crime.Location
+	officer.DestinationID = crime.BuildingID
+	officer.Route = nil
+}
+
+// Respond an officer arriving at the crime, recording the response time and
+// rolling for an arrest
+func Respond(rng *rand.Rand, officer *commonModels.Person, crime *commonModels.Crime, t time.Time) bool {
+	officer.Responding = ""
+	crime.Responded = t
+	response := t.Sub(crime.Time)
+	crime.ResponseTime = int(response.Seconds())
+	chance := ARRESTCHANCE * math.Pow(0.5, float64(response)/float64(ARRESTHALFLIFE))
+	crime.Arrested = crime.PerpetratorID.Valid() && rng.Float64() < chance
+	return crime.Arrested
+}
+
+// Deterrence chance a crime at location still goes ahead given the recent
+// arrests in the city
+func Deterrence(crimes []commonModels.Crime, location Point, t time.Time) float64 {
+	arrests := 0
+	for _, c := range crimes {
+		if c.Arrested && t.Sub(c.Time) <= ARRESTMEMORY && geometry.Distance(c.Location, location) <= PATROLRADIUS {
+			arrests++
+		}
+	}
+	return 1 / (1 + DETERRENCE*float64(arrests))
+}
@@ -0,0 +1,41 @@
+package random
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// New rng derived from the world seed and keys, the same seed and keys
+// always give the same sequence
+func New(seed int64, keys ...string) *rand.Rand {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, seed)
+	for _, key := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// ForTick rng for the current tick of the world
+func ForTick(settings commonModels.Settings, keys ...string) *rand.Rand {
+	return New(settings.Seed, append([]string{strconv.FormatInt(settings.Tick, 10)}, keys...)...)
+}
+
+// ForEntity rng for one entity during the current tick, so the result does
+// not depend on which worker picks the entity up or in what order
+func ForEntity(settings commonModels.Settings, id bson.ObjectId, keys ...string) *rand.Rand {
+	return ForTick(settings, append([]string{id.Hex()}, keys...)...)
+}
+
+// ObjectID an ID drawn from rng instead of the clock and machine id
+func ObjectID(rng *rand.Rand) bson.ObjectId {
+	b := make([]byte, 12)
+	rng.Read(b)
+	return bson.ObjectId(b)
+}
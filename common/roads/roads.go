@@ -0,0 +1,200 @@
+package roads
+
+import (
+	"container/heap"
+	. "image"
+	"math"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+// BLOCK distance in feet between streets in a city grid, an eighth of a mile
+const BLOCK = 660
+
+// Grid streets every BLOCK feet across the city, split at each intersection
+func Grid(city commonModels.City) []commonModels.Road {
+	roads := []commonModels.Road{}
+	xs := lines(city.TopLeft.X, city.BottomRight.X)
+	ys := lines(city.TopLeft.Y, city.BottomRight.Y)
+	for _, x := range xs {
+		for i := 1; i < len(ys); i++ {
+			roads = append(roads, commonModels.Road{From: Point{X: x, Y: ys[i-1]}, To: Point{X: x, Y: ys[i]}, Class: commonModels.Street})
+		}
+	}
+	for _, y := range ys {
+		for i := 1; i < len(xs); i++ {
+			roads = append(roads, commonModels.Road{From: Point{X: xs[i-1], Y: y}, To: Point{X: xs[i], Y: y}, Class: commonModels.Street})
+		}
+	}
+	return roads
+}
+
+func lines(min int, max int) []int {
+	list := []int{}
+	for v := min; v < max; v += BLOCK {
+		list = append(list, v)
+	}
+	return append(list, max)
+}
+
+// Connect a highway between the closest intersections of two cities
+func Connect(from commonModels.City, to commonModels.City) []commonModels.Road {
+	start := Nearest(from.Roads, geometry.Center(geometry.City(to)))
+	end := Nearest(to.Roads, geometry.Center(geometry.City(from)))
+	return []commonModels.Road{{From: start, To: end, Class: commonModels.Highway}}
+}
+
+// Nearest the road end closest to p
+func Nearest(roads []commonModels.Road, p Point) Point {
+	best := p
+	bestDist := math.Inf(1)
+	for _, road := range roads {
+		for _, end := range []Point{road.From, road.To} {
+			if d := geometry.Distance(end, p); d < bestDist {
+				best, bestDist = end, d
+			}
+		}
+	}
+	return best
+}
+
+type edge struct {
+	to    Point
+	class commonModels.RoadClass
+	secs  float64
+}
+
+// Graph road network used for routing, edge costs are travel time
+type Graph struct {
+	nodes  map[Point][]edge
+	speeds map[commonModels.RoadClass]float64
+}
+
+// NewGraph build the network from city streets and highways. speeds are the
+// speed limits in mph for each road class.
+func NewGraph(speeds map[commonModels.RoadClass]int, cities []commonModels.City, highways []commonModels.HighwayRoute) *Graph {
+	g := &Graph{nodes: map[Point][]edge{}, speeds: map[commonModels.RoadClass]float64{}}
+	for class, mph := range speeds {
+		g.speeds[class] = geometry.FeetPerSecond(float64(mph))
+	}
+	for _, city := range cities {
+		for _, road := range city.Roads {
+			g.add(road)
+		}
+	}
+	for _, highway := range highways {
+		for _, road := range highway.Roads {
+			g.add(road)
+		}
+	}
+	return g
+}
+
+func (g *Graph) add(road commonModels.Road) {
+	speed := g.speeds[road.Class]
+	if speed <= 0 {
+		return
+	}
+	secs := geometry.Distance(road.From, road.To) / speed
+	g.nodes[road.From] = append(g.nodes[road.From], edge{to: road.To, class: road.Class, secs: secs})
+	g.nodes[road.To] = append(g.nodes[road.To], edge{to: road.From, class: road.Class, secs: secs})
+}
+
+func (g *Graph) nearest(p Point) (Point, bool) {
+	best := p
+	bestDist := math.Inf(1)
+	for node := range g.nodes {
+		d := geometry.Distance(node, p)
+		if d < bestDist || (d == bestDist && (node.X < best.X || (node.X == best.X && node.Y < best.Y))) {
+			best, bestDist = node, d
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+func (g *Graph) fastest() float64 {
+	fastest := 0.0
+	for _, speed := range g.speeds {
+		fastest = math.Max(fastest, speed)
+	}
+	return fastest
+}
+
+// Route the quickest way from one point to another using A*. The route starts
+// at the intersection nearest from and ends at to, secs is the time spent on
+// the roads. ok is false if the two points aren't connected.
+func (g *Graph) Route(from Point, to Point) (route []commonModels.Waypoint, secs float64, ok bool) {
+	start, ok := g.nearest(from)
+	if !ok {
+		return nil, 0, false
+	}
+	end, _ := g.nearest(to)
+	fastest := g.fastest()
+
+	type step struct {
+		from  Point
+		class commonModels.RoadClass
+	}
+	came := map[Point]step{}
+	cost := map[Point]float64{start: 0}
+	open := &queue{}
+	heap.Push(open, &item{point: start, priority: geometry.Distance(start, end) / fastest})
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*item).point
+		if current == end {
+			break
+		}
+		for _, e := range g.nodes[current] {
+			c := cost[current] + e.secs
+			if old, seen := cost[e.to]; seen && c >= old {
+				continue
+			}
+			cost[e.to] = c
+			came[e.to] = step{from: current, class: e.class}
+			heap.Push(open, &item{point: e.to, priority: c + geometry.Distance(e.to, end)/fastest})
+		}
+	}
+	secs, ok = cost[end]
+	if !ok {
+		return nil, 0, false
+	}
+
+	route = []commonModels.Waypoint{{Point: to}}
+	for p := end; p != start; p = came[p].from {
+		route = append(route, commonModels.Waypoint{Point: p, Class: came[p].class})
+	}
+	route = append(route, commonModels.Waypoint{Point: start})
+	for i, j := 0, len(route)-1; i < j; i, j = i+1, j-1 {
+		route[i], route[j] = route[j], route[i]
+	}
+	return route, secs, true
+}
+
+type item struct {
+	point    Point
+	priority float64
+}
+
+type queue []*item
+
+func (q queue) Len() int { return len(q) }
+
+// Less orders by priority, ties go to the smaller point so equal routes are
+// picked the same way whatever order the roads were added in
+func (q queue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	a, b := q[i].point, q[j].point
+	return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+}
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(*item)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[:n-1]
+	return it
+}
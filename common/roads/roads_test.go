@@ -0,0 +1,90 @@
+package roads
+
+import (
+	. "image"
+	"reflect"
+	"testing"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+var speeds = map[commonModels.RoadClass]int{commonModels.Street: 25, commonModels.Highway: 65}
+
+func road(from Point, to Point, class commonModels.RoadClass) commonModels.Road {
+	return commonModels.Road{From: from, To: to, Class: class}
+}
+
+func points(route []commonModels.Waypoint) []Point {
+	list := []Point{}
+	for _, w := range route {
+		list = append(list, w.Point)
+	}
+	return list
+}
+
+func TestRoute(t *testing.T) {
+	// a block with two equally quick ways around it
+	square := []commonModels.Road{
+		road(Point{X: 0, Y: 0}, Point{X: 1000, Y: 0}, commonModels.Street),
+		road(Point{X: 0, Y: 0}, Point{X: 0, Y: 1000}, commonModels.Street),
+		road(Point{X: 1000, Y: 0}, Point{X: 1000, Y: 1000}, commonModels.Street),
+		road(Point{X: 0, Y: 1000}, Point{X: 1000, Y: 1000}, commonModels.Street),
+	}
+	reversed := []commonModels.Road{square[3], square[2], square[1], square[0]}
+	// a straight street and a longer but faster highway around it
+	bypass := []commonModels.Road{
+		road(Point{X: 0, Y: 0}, Point{X: 10000, Y: 0}, commonModels.Street),
+		road(Point{X: 0, Y: 0}, Point{X: 5000, Y: 3000}, commonModels.Highway),
+		road(Point{X: 5000, Y: 3000}, Point{X: 10000, Y: 0}, commonModels.Highway),
+	}
+	tests := []struct {
+		name  string
+		roads []commonModels.Road
+		from  Point
+		to    Point
+		want  []Point
+	}{
+		{"tie", square, Point{X: 0, Y: 0}, Point{X: 1000, Y: 1000}, []Point{{X: 0, Y: 0}, {X: 0, Y: 1000}, {X: 1000, Y: 1000}, {X: 1000, Y: 1000}}},
+		{"tie with the roads reversed", reversed, Point{X: 0, Y: 0}, Point{X: 1000, Y: 1000}, []Point{{X: 0, Y: 0}, {X: 0, Y: 1000}, {X: 1000, Y: 1000}, {X: 1000, Y: 1000}}},
+		{"faster road wins", bypass, Point{X: 0, Y: 0}, Point{X: 10000, Y: 0}, []Point{{X: 0, Y: 0}, {X: 5000, Y: 3000}, {X: 10000, Y: 0}, {X: 10000, Y: 0}}},
+		{"from the nearest intersection", bypass, Point{X: 10, Y: 10}, Point{X: 10000, Y: 0}, []Point{{X: 0, Y: 0}, {X: 5000, Y: 3000}, {X: 10000, Y: 0}, {X: 10000, Y: 0}}},
+	}
+	for _, tt := range tests {
+		g := NewGraph(speeds, []commonModels.City{{Roads: tt.roads}}, nil)
+		route, secs, ok := g.Route(tt.from, tt.to)
+		if !ok {
+			t.Errorf("%s: no route", tt.name)
+			continue
+		}
+		if got := points(route); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: route %v, want %v", tt.name, got, tt.want)
+		}
+		if secs <= 0 {
+			t.Errorf("%s: expected the route to take time, got %f", tt.name, secs)
+		}
+	}
+}
+
+func TestRouteUnconnected(t *testing.T) {
+	g := NewGraph(speeds, []commonModels.City{{Roads: []commonModels.Road{
+		road(Point{X: 0, Y: 0}, Point{X: 1000, Y: 0}, commonModels.Street),
+		road(Point{X: 5000, Y: 0}, Point{X: 6000, Y: 0}, commonModels.Street),
+	}}}, nil)
+	if _, _, ok := g.Route(Point{X: 0, Y: 0}, Point{X: 6000, Y: 0}); ok {
+		t.Errorf("expected no route between unconnected roads")
+	}
+	if _, _, ok := NewGraph(speeds, nil, nil).Route(Point{X: 0, Y: 0}, Point{X: 1, Y: 1}); ok {
+		t.Errorf("expected no route on an empty graph")
+	}
+}
+
+func TestNearestTie(t *testing.T) {
+	g := NewGraph(speeds, []commonModels.City{{Roads: []commonModels.Road{
+		road(Point{X: 1000, Y: 0}, Point{X: 0, Y: 0}, commonModels.Street),
+	}}}, nil)
+	for i := 0; i < 20; i++ {
+		if p, _ := g.nearest(Point{X: 500, Y: 0}); p != (Point{X: 0, Y: 0}) {
+			t.Fatalf("expected the tie to go to the smaller point, got %v", p)
+		}
+	}
+}
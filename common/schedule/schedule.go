@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"math/rand"
+	"time"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Activity what a person's schedule has them doing
+type Activity int
+
+const (
+	// Asleep in bed at home
+	Asleep Activity = iota + 1
+	// Morning up and getting ready at home
+	Morning
+	// Commuting on the way to work or school
+	Commuting
+	// Working at work or school
+	Working
+	// Free time after work or on a day off, they can go where they like
+	Free
+	// Returning heading home for the night
+	Returning
+)
+
+// Default the routine of a typical nine to five worker
+func Default() commonModels.Schedule {
+	return commonModels.Schedule{
+		Wake:      7 * 60,
+		WorkStart: 9 * 60,
+		WorkEnd:   17 * 60,
+		Bedtime:   23 * 60,
+		Commute:   30,
+		Weekends:  false,
+	}
+}
+
+// New a default routine shifted up to an hour either way so people don't all
+// leave on the same tick, with some people working weekends
+func New(rng *rand.Rand) commonModels.Schedule {
+	s := Default()
+	shift := rng.Intn(121) - 60
+	s.Wake += shift
+	s.WorkStart += shift
+	s.WorkEnd += shift
+	s.Bedtime += rng.Intn(121) - 60
+	s.Weekends = rng.Float64() < 0.15
+	return s
+}
+
+// Workday whether t is a work day for the schedule
+func Workday(s commonModels.Schedule, t time.Time) bool {
+	if s.Weekends {
+		return true
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// Workplace where the person spends the working day, school for students
+func Workplace(person commonModels.Person) bson.ObjectId {
+	if person.School.Valid() {
+		return person.School
+	}
+	return person.WorkBuilding
+}
+
+// Now what the schedule has the person doing at t
+func Now(person commonModels.Person, t time.Time) Activity {
+	s := person.Schedule
+	if s.Bedtime == 0 {
+		s = Default()
+	}
+	minute := t.Hour()*60 + t.Minute()
+	work := Workplace(person)
+	commutes := work.Valid() && work != person.HomeBuilding && Workday(s, t)
+	switch {
+	case minute < s.Wake:
+		return Asleep
+	case minute >= s.Bedtime:
+		return Asleep
+	case commutes && minute >= s.WorkStart && minute < s.WorkEnd:
+		return Working
+	case commutes && minute >= s.WorkStart-s.Commute && minute < s.WorkStart:
+		return Commuting
+	case commutes && minute < s.WorkStart:
+		return Morning
+	case minute >= s.Bedtime-s.Commute:
+		return Returning
+	}
+	return Free
+}
+
+// Target the building the person should be in at t, empty if they're free to
+// be anywhere
+func Target(person commonModels.Person, t time.Time) (bson.ObjectId, Activity) {
+	activity := Now(person, t)
+	switch activity {
+	case Commuting, Working:
+		return Workplace(person), activity
+	case Asleep, Morning, Returning:
+		return person.HomeBuilding, activity
+	}
+	return "", activity
+}
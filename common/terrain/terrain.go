@@ -0,0 +1,237 @@
+package terrain
+
+import (
+	. "image"
+	"math"
+	"sync"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+)
+
+const (
+	// CELLSIZE width and height of a terrain cell, a mile
+	CELLSIZE = geometry.FEETPERMILE
+	// CELLS cells across a tile
+	CELLS = 10
+	// TILESIZE width and height of a tile
+	TILESIZE = CELLS * CELLSIZE
+	// PEAK elevation in feet of the highest ground, the deepest water is
+	// about SEALEVEL/(1-SEALEVEL) as far below sea level, some 4300 feet
+	PEAK = 8000
+	// SEALEVEL share of the elevation range that is under water
+	SEALEVEL = 0.35
+	// TREELINE elevation in feet above which the ground is mountains
+	TREELINE = 4000
+	// FARMLINE elevation in feet above which nothing is farmed
+	FARMLINE = 1500
+)
+
+// octaves scales in feet of the noise layered into elevation and moisture,
+// continents down to hills
+var octaves = []float64{400 * geometry.FEETPERMILE, 100 * geometry.FEETPERMILE, 25 * geometry.FEETPERMILE, 6 * geometry.FEETPERMILE}
+
+// Generate the terrain tile at x, y of a world. The same seed always gives
+// the same terrain.
+func Generate(seed int64, x int, y int) commonModels.TerrainTile {
+	tile := commonModels.TerrainTile{X: x, Y: y, Elevation: make([]int, CELLS*CELLS), Cover: make([]commonModels.LandCover, CELLS*CELLS)}
+	for row := 0; row < CELLS; row++ {
+		for col := 0; col < CELLS; col++ {
+			center := Point{X: x*TILESIZE + col*CELLSIZE + CELLSIZE/2, Y: y*TILESIZE + row*CELLSIZE + CELLSIZE/2}
+			tile.Cover[row*CELLS+col], tile.Elevation[row*CELLS+col] = cell(seed, center)
+		}
+	}
+	return tile
+}
+
+// At the land cover and elevation of the cell containing p, the same as the
+// generated tile would have without generating or loading it
+func At(seed int64, p Point) (commonModels.LandCover, int) {
+	center := Point{X: floorDiv(p.X, CELLSIZE)*CELLSIZE + CELLSIZE/2, Y: floorDiv(p.Y, CELLSIZE)*CELLSIZE + CELLSIZE/2}
+	return cell(seed, center)
+}
+
+// cell the land cover and elevation at the center of a cell
+func cell(seed int64, center Point) (commonModels.LandCover, int) {
+	elevation := int((fractal(seed, 1, center) - SEALEVEL) / (1 - SEALEVEL) * PEAK)
+	return cover(elevation, fractal(seed, 2, center)), elevation
+}
+
+// cover the land cover for an elevation and moisture between 0 and 1
+func cover(elevation int, moisture float64) commonModels.LandCover {
+	switch {
+	case elevation < 0:
+		return commonModels.Water
+	case elevation >= TREELINE:
+		return commonModels.Mountains
+	case moisture > 0.6:
+		return commonModels.Forest
+	case moisture > 0.4 && elevation < FARMLINE:
+		return commonModels.Farmland
+	}
+	return commonModels.Plains
+}
+
+// SpeedFactor share of the speed limit traffic keeps crossing a kind of land
+func SpeedFactor(cover commonModels.LandCover) float64 {
+	switch cover {
+	case commonModels.Forest:
+		return 0.85
+	case commonModels.Mountains:
+		return 0.6
+	}
+	return 1
+}
+
+// Buildable whether cities can be built on a kind of land
+func Buildable(cover commonModels.LandCover) bool {
+	return cover != commonModels.Water && cover != commonModels.Mountains
+}
+
+// fractal layered value noise between 0 and 1 at p, layer picks independent
+// noise for the same seed
+func fractal(seed int64, layer uint64, p Point) float64 {
+	total, weight, amplitude := 0.0, 0.0, 1.0
+	for i, scale := range octaves {
+		total += amplitude * noise(seed, layer*16+uint64(i), float64(p.X)/scale, float64(p.Y)/scale)
+		weight += amplitude
+		amplitude /= 2
+	}
+	return total / weight
+}
+
+// noise smoothly interpolated random values on an integer lattice
+func noise(seed int64, layer uint64, x float64, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := smooth(x-x0), smooth(y-y0)
+	ix, iy := int64(x0), int64(y0)
+	top := lerp(lattice(seed, layer, ix, iy), lattice(seed, layer, ix+1, iy), fx)
+	bottom := lerp(lattice(seed, layer, ix, iy+1), lattice(seed, layer, ix+1, iy+1), fx)
+	return lerp(top, bottom, fy)
+}
+
+// lattice a random value between 0 and 1 for a lattice point
+func lattice(seed int64, layer uint64, x int64, y int64) float64 {
+	h := uint64(seed) ^ layer*0x9E3779B97F4A7C15
+	h ^= uint64(x) * 0xBF58476D1CE4E5B9
+	h ^= uint64(y) * 0x94D049BB133111EB
+	h ^= h >> 30
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 27
+	h *= 0x94D049BB133111EB
+	h ^= h >> 31
+	return float64(h>>11) / float64(1<<53)
+}
+
+func smooth(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a float64, b float64, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// TileStore where a Map keeps the tiles it generates
+type TileStore interface {
+	GetTerrainTile(x int, y int) (commonModels.TerrainTile, error)
+	SaveTerrainTile(tile commonModels.TerrainTile) error
+}
+
+// Map the terrain of a world, tiles are loaded from the store or generated
+// and saved the first time they're needed
+type Map struct {
+	seed  int64
+	store TileStore
+	mu    sync.Mutex
+	tiles map[Point]commonModels.TerrainTile
+}
+
+// NewMap the terrain of the world with these settings
+func NewMap(settings commonModels.Settings, store TileStore) *Map {
+	return &Map{seed: settings.Seed, store: store, tiles: map[Point]commonModels.TerrainTile{}}
+}
+
+// Seed the seed of the world the terrain belongs to
+func (m *Map) Seed() int64 {
+	return m.seed
+}
+
+// Tile the tile at x, y, saving it if it hasn't been generated before
+func (m *Map) Tile(x int, y int) (commonModels.TerrainTile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := Point{X: x, Y: y}
+	if tile, ok := m.tiles[key]; ok {
+		return tile, nil
+	}
+	tile, err := m.store.GetTerrainTile(x, y)
+	if err != nil || len(tile.Cover) != CELLS*CELLS {
+		tile = Generate(m.seed, x, y)
+		if err = m.store.SaveTerrainTile(tile); err != nil {
+			return tile, err
+		}
+	}
+	m.tiles[key] = tile
+	return tile, nil
+}
+
+// cellAt the tile and index of the cell containing p
+func (m *Map) cellAt(p Point) (commonModels.TerrainTile, int) {
+	x, y := floorDiv(p.X, TILESIZE), floorDiv(p.Y, TILESIZE)
+	// a tile that couldn't be saved is still the right terrain
+	tile, _ := m.Tile(x, y)
+	col := (p.X - x*TILESIZE) / CELLSIZE
+	row := (p.Y - y*TILESIZE) / CELLSIZE
+	return tile, row*CELLS + col
+}
+
+func floorDiv(a int, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// Cover the land cover at p
+func (m *Map) Cover(p Point) commonModels.LandCover {
+	tile, i := m.cellAt(p)
+	return tile.Cover[i]
+}
+
+// Elevation feet above sea level at p
+func (m *Map) Elevation(p Point) int {
+	tile, i := m.cellAt(p)
+	return tile.Elevation[i]
+}
+
+// SpeedFactor share of the speed limit traffic keeps at p
+func (m *Map) SpeedFactor(p Point) float64 {
+	return SpeedFactor(m.Cover(p))
+}
+
+// Buildable whether every cell under a rectangle can be built on
+func (m *Map) Buildable(r Rectangle) bool {
+	for y := floorDiv(r.Min.Y, CELLSIZE); y*CELLSIZE < r.Max.Y; y++ {
+		for x := floorDiv(r.Min.X, CELLSIZE); x*CELLSIZE < r.Max.X; x++ {
+			if !Buildable(m.Cover(Point{X: x * CELLSIZE, Y: y * CELLSIZE})) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Passable whether a road can run straight from one point to another without
+// crossing water
+func (m *Map) Passable(from Point, to Point) bool {
+	steps := int(geometry.Distance(from, to)/(CELLSIZE/2)) + 1
+	for i := 0; i <= steps; i++ {
+		f := float64(i) / float64(steps)
+		p := Point{X: from.X + int(f*float64(to.X-from.X)), Y: from.Y + int(f*float64(to.Y-from.Y))}
+		if m.Cover(p) == commonModels.Water {
+			return false
+		}
+	}
+	return true
+}
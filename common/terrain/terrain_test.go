@@ -0,0 +1,120 @@
+package terrain
+
+import (
+	. "image"
+	"reflect"
+	"testing"
+
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	mgo "gopkg.in/mgo.v2"
+)
+
+// tiles a TileStore that counts saves
+type tiles struct {
+	saved map[Point]commonModels.TerrainTile
+	saves int
+}
+
+func (s *tiles) GetTerrainTile(x int, y int) (commonModels.TerrainTile, error) {
+	tile, ok := s.saved[Point{X: x, Y: y}]
+	if !ok {
+		return tile, mgo.ErrNotFound
+	}
+	return tile, nil
+}
+
+func (s *tiles) SaveTerrainTile(tile commonModels.TerrainTile) error {
+	s.saved[Point{X: tile.X, Y: tile.Y}] = tile
+	s.saves++
+	return nil
+}
+
+func TestGenerateSameSeedSameTile(t *testing.T) {
+	for _, at := range []Point{{X: 0, Y: 0}, {X: 3, Y: 7}, {X: -2, Y: 5}} {
+		a, b := Generate(42, at.X, at.Y), Generate(42, at.X, at.Y)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("tile %v differs for the same seed", at)
+		}
+		if len(a.Elevation) != CELLS*CELLS || len(a.Cover) != CELLS*CELLS {
+			t.Errorf("tile %v has %d cells, want %d", at, len(a.Cover), CELLS*CELLS)
+		}
+		if reflect.DeepEqual(a, Generate(43, at.X, at.Y)) {
+			t.Errorf("tile %v is the same for different seeds", at)
+		}
+	}
+}
+
+func TestGenerateElevationRange(t *testing.T) {
+	lowest := -SEALEVEL / (1 - SEALEVEL) * PEAK
+	for x := 0; x < 20; x++ {
+		tile := Generate(7, x, x)
+		for i, e := range tile.Elevation {
+			if float64(e) < lowest-1 || e > PEAK {
+				t.Fatalf("elevation %d outside %.0f to %d", e, lowest, PEAK)
+			}
+			if (e < 0) != (tile.Cover[i] == commonModels.Water) {
+				t.Fatalf("elevation %d has cover %v", e, tile.Cover[i])
+			}
+		}
+	}
+}
+
+func TestMapTileGeneratesOnce(t *testing.T) {
+	store := &tiles{saved: map[Point]commonModels.TerrainTile{}}
+	m := NewMap(commonModels.Settings{Seed: 42}, store)
+	first, err := m.Tile(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, Generate(42, 1, 2)) {
+		t.Errorf("map tile isn't the generated tile")
+	}
+	m.Tile(1, 2)
+	// a fresh map over the same store loads the saved tile
+	again, _ := NewMap(commonModels.Settings{Seed: 42}, store).Tile(1, 2)
+	if store.saves != 1 || !reflect.DeepEqual(first, again) {
+		t.Errorf("expected the tile to be generated and saved once, saved %d times", store.saves)
+	}
+}
+
+func TestBuildable(t *testing.T) {
+	tests := []struct {
+		cover commonModels.LandCover
+		want  bool
+	}{
+		{commonModels.Plains, true},
+		{commonModels.Farmland, true},
+		{commonModels.Forest, true},
+		{commonModels.Water, false},
+		{commonModels.Mountains, false},
+	}
+	for _, tt := range tests {
+		if got := Buildable(tt.cover); got != tt.want {
+			t.Errorf("Buildable(%v) = %v, want %v", tt.cover, got, tt.want)
+		}
+	}
+
+	m := NewMap(commonModels.Settings{Seed: 42}, &tiles{saved: map[Point]commonModels.TerrainTile{}})
+	// every cell a rectangle touches counts, including ones it only clips
+	for y := 0; y < CELLS; y++ {
+		for x := 0; x < CELLS; x++ {
+			cell := Rect(x*CELLSIZE, y*CELLSIZE, (x+1)*CELLSIZE, (y+1)*CELLSIZE)
+			if got, want := m.Buildable(cell), Buildable(m.Cover(cell.Min)); got != want {
+				t.Errorf("cell %d,%d: Buildable %v, cover says %v", x, y, got, want)
+			}
+			if !Buildable(m.Cover(cell.Min)) && m.Buildable(cell.Inset(CELLSIZE/4).Add(Point{X: CELLSIZE / 2, Y: 0})) {
+				t.Errorf("cell %d,%d: a rectangle overlapping an unbuildable cell is buildable", x, y)
+			}
+		}
+	}
+}
+
+func TestAtMatchesGenerate(t *testing.T) {
+	tile := Generate(42, -1, 2)
+	for i := range tile.Cover {
+		p := Point{X: -TILESIZE + (i%CELLS)*CELLSIZE + 1, Y: 2*TILESIZE + (i/CELLS)*CELLSIZE + CELLSIZE - 1}
+		if cover, elevation := At(42, p); cover != tile.Cover[i] || elevation != tile.Elevation[i] {
+			t.Errorf("cell %d: At gives %v %d, the tile has %v %d", i, cover, elevation, tile.Cover[i], tile.Elevation[i])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package traffic
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/toasterlint/DAWS/common/demographics"
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/random"
+)
+
+const (
+	// MILESPERYEAR miles a person drives in a year, turns the yearly fatality
+	// rate into a rate per mile
+	MILESPERYEAR = 13476
+	// REFERENCEMPH speed at which the fatality rate applies as is, faster roads
+	// are deadlier and slower ones safer
+	REFERENCEMPH = 45
+	// INJURIESPERFATALITY accidents with injuries for every fatal one
+	INJURIESPERFATALITY = 70
+)
+
+// FatalityChance chance of a fatal accident over a leg
+func FatalityChance(settings commonModels.Settings, leg Leg) float64 {
+	miles := geometry.Miles(leg.Feet())
+	return float64(settings.CarAccidentFatalityRate) / MILESPERYEAR * miles * float64(leg.MPH) / REFERENCEMPH
+}
+
+// Crash roll for an accident over each leg a traveler covered this tick. The
+// traveler is injured or killed and the accident is returned.
+func Crash(rng *rand.Rand, settings commonModels.Settings, person *commonModels.Person, legs []Leg, t time.Time) (commonModels.CarAccident, bool) {
+	for _, leg := range legs {
+		fatal := FatalityChance(settings, leg)
+		roll := rng.Float64()
+		if roll >= fatal*(1+INJURIESPERFATALITY) {
+			continue
+		}
+		accident := commonModels.CarAccident{
+			ID:       random.ObjectID(rng),
+			Time:     t,
+			PersonID: person.ID,
+			Location: leg.To,
+			Speed:    leg.MPH,
+		}
+		if roll < fatal {
+			accident.Injury = person.Health
+		} else {
+			// faster crashes hurt more
+			accident.Injury = (10 + rng.Intn(41)) * leg.MPH / REFERENCEMPH
+		}
+		person.Health -= accident.Injury
+		if person.Health <= 0 {
+			person.Health = 0
+			accident.Fatal = true
+			demographics.Die(person, t, commonModels.Accident)
+		}
+		return accident, true
+	}
+	return commonModels.CarAccident{}, false
+}
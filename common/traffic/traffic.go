@@ -0,0 +1,185 @@
+package traffic
+
+import (
+	. "image"
+	"math"
+
+	"github.com/toasterlint/DAWS/common/geometry"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/roads"
+)
+
+const (
+	// SECONDSPERTICK simulated time that passes each tick
+	SECONDSPERTICK = 1
+	// CITY speed limit location inside a city
+	CITY = "city"
+	// NONCITY speed limit location outside every city
+	NONCITY = "noncity"
+)
+
+// SpeedLimit speed limit in mph for the location, 0 if the world has none
+func SpeedLimit(settings commonModels.Settings, location string) int {
+	for _, limit := range settings.SpeedLimits {
+		if limit.Location == location {
+			return limit.Value
+		}
+	}
+	return 0
+}
+
+// FeetPerTick distance covered in one tick at mph
+func FeetPerTick(mph int) float64 {
+	return geometry.FeetPerSecond(float64(mph)) * SECONDSPERTICK
+}
+
+// InCity the city containing p, if any
+func InCity(cities []commonModels.City, p Point) (commonModels.City, bool) {
+	for _, city := range cities {
+		if geometry.Contains(geometry.City(city), p) {
+			return city, true
+		}
+	}
+	return commonModels.City{}, false
+}
+
+// Location speed limit location for p
+func Location(cities []commonModels.City, p Point) string {
+	if _, ok := InCity(cities, p); ok {
+		return CITY
+	}
+	return NONCITY
+}
+
+// MoveToward move from toward to by at most feet, returning the new point and
+// whether to was reached
+func MoveToward(from Point, to Point, feet float64) (Point, bool) {
+	dist := geometry.Distance(from, to)
+	if dist <= feet {
+		return to, true
+	}
+	ratio := feet / dist
+	return Point{
+		X: from.X + int(math.Round(float64(to.X-from.X)*ratio)),
+		Y: from.Y + int(math.Round(float64(to.Y-from.Y)*ratio)),
+	}, false
+}
+
+// RoadSpeeds speed limit in mph for each class of road
+func RoadSpeeds(settings commonModels.Settings) map[commonModels.RoadClass]int {
+	return map[commonModels.RoadClass]int{
+		commonModels.Street:  SpeedLimit(settings, CITY),
+		commonModels.Highway: SpeedLimit(settings, NONCITY),
+	}
+}
+
+// NewGraph road network of the world with the world's speed limits
+func NewGraph(settings commonModels.Settings, cities []commonModels.City, highways []commonModels.HighwayRoute) *roads.Graph {
+	return roads.NewGraph(RoadSpeeds(settings), cities, highways)
+}
+
+// Plan give a traveler a route to their destination. If the roads don't
+// connect them the route goes straight there, so it's only planned once.
+// Returns whether the route is on the roads.
+func Plan(graph *roads.Graph, person *commonModels.Person) bool {
+	route, _, ok := graph.Route(person.CurrentXY, person.Destination)
+	if !ok {
+		route = []commonModels.Waypoint{{Point: person.Destination}}
+	}
+	person.Route = route
+	return ok
+}
+
+// CommuteMinutes how long the drive between two points takes, rounded up,
+// 0 if the roads don't connect them
+func CommuteMinutes(graph *roads.Graph, from Point, to Point) int {
+	_, secs, ok := graph.Route(from, to)
+	if !ok {
+		return 0
+	}
+	return int(math.Ceil(secs / 60))
+}
+
+// Terrain slows traffic outside cities, SpeedFactor is the share of the speed
+// limit kept at a point
+type Terrain interface {
+	SpeedFactor(p Point) float64
+}
+
+// speed mph for the stretch leading to a waypoint, off road stretches use
+// the limit of where the traveler is. Outside cities the terrain slows
+// traffic down, land may be nil for open plains everywhere.
+func speed(settings commonModels.Settings, cities []commonModels.City, land Terrain, at Point, class commonModels.RoadClass) int {
+	location := Location(cities, at)
+	switch class {
+	case commonModels.Street:
+		location = CITY
+	case commonModels.Highway:
+		location = NONCITY
+	}
+	mph := SpeedLimit(settings, location)
+	if location == NONCITY && land != nil {
+		mph = int(float64(mph) * land.SpeedFactor(at))
+	}
+	return mph
+}
+
+// Leg a stretch covered at one speed during a tick
+type Leg struct {
+	From Point
+	To   Point
+	MPH  int
+}
+
+// Feet length of the leg
+func (l Leg) Feet() float64 {
+	return geometry.Distance(l.From, l.To)
+}
+
+// Advance move a traveling person one tick along their route, or straight
+// toward their destination without one, returning the legs traveled. On
+// arrival the person is placed in the destination building.
+func Advance(settings commonModels.Settings, cities []commonModels.City, land Terrain, person *commonModels.Person) []Leg {
+	legs := []Leg{}
+	secs := float64(SECONDSPERTICK)
+	for secs > 0 && person.Traveling {
+		next := commonModels.Waypoint{Point: person.Destination}
+		if len(person.Route) > 0 {
+			next = person.Route[0]
+		}
+		mph := speed(settings, cities, land, person.CurrentXY, next.Class)
+		feetPerSec := FeetPerTick(mph) / SECONDSPERTICK
+		if feetPerSec <= 0 {
+			break
+		}
+		leg := Leg{From: person.CurrentXY, MPH: mph}
+		var reached bool
+		person.CurrentXY, reached = MoveToward(person.CurrentXY, next.Point, feetPerSec*secs)
+		leg.To = person.CurrentXY
+		if leg.Feet() > 0 {
+			legs = append(legs, leg)
+		}
+		secs -= leg.Feet() / feetPerSec
+		if !reached {
+			break
+		}
+		if len(person.Route) > 0 {
+			person.Route = person.Route[1:]
+		}
+		if len(person.Route) == 0 && person.CurrentXY == person.Destination {
+			Arrive(person)
+		}
+	}
+	return legs
+}
+
+// Arrive place a traveler in their destination building
+func Arrive(person *commonModels.Person) {
+	person.CurrentXY = person.Destination
+	person.CurrentBuilding = person.DestinationID
+	person.Traveling = false
+	person.NewToBuilding = true
+	person.DestinationID = ""
+	person.Destination = Point{}
+	person.Route = nil
+}
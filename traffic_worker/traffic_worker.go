@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	uuid "github.com/nu7hatch/gouuid"
+	"github.com/toasterlint/DAWS/common/broker"
+	"github.com/toasterlint/DAWS/common/config"
+	. "github.com/toasterlint/DAWS/common/dao"
+	"github.com/toasterlint/DAWS/common/demographics"
+	commonModels "github.com/toasterlint/DAWS/common/models"
+	"github.com/toasterlint/DAWS/common/police"
+	"github.com/toasterlint/DAWS/common/random"
+	"github.com/toasterlint/DAWS/common/roads"
+	"github.com/toasterlint/DAWS/common/terrain"
+	"github.com/toasterlint/DAWS/common/traffic"
+	. "github.com/toasterlint/DAWS/common/utils"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var settings commonModels.Settings
+var mq broker.Broker
+var trafficjobq broker.Queue
+var msgs <-chan broker.Delivery
+var cfg config.Config
+var store WorldStore
+var myself commonModels.Worker
+var graph *roads.Graph
+var graphCities []commonModels.City
+var graphMinute int64
+var terrainMap *terrain.Map
+
+func runConsole() {
+	// setup terminal
+	reader := bufio.NewReader(os.Stdin)
+ReadCommand:
+	Logger.Print("Command: ")
+	text, _ := reader.ReadString('\n')
+	text = strings.Trim(text, "\n")
+	switch text {
+	case "exit":
+		Logger.Println("Exiting...")
+		os.Exit(0)
+	case "status":
+		Logger.Println("Waiting for jobs from Traffic Controllers...")
+		tempTrafficJobQ, err := mq.QueueInspect(trafficjobq.Name)
+		FailOnError(err, "Failed to check Traffic Job Queue")
+		Logger.Printf("Traffic Workers: %d", tempTrafficJobQ.Consumers)
+		Logger.Printf("Traffic Jobs Waiting: %d", tempTrafficJobQ.Messages)
+	case "help":
+		fallthrough
+	default:
+		Logger.Println("Help: ")
+		Logger.Println("   status - Check the status of the world")
+		Logger.Println("   exit - Exit the App")
+	}
+	goto ReadCommand
+}
+
+func connectQueues() {
+	var err error
+	mq, err = cfg.NewBroker()
+	FailOnError(err, "Failed to connect to RabbitMQ")
+
+	trafficjobq, err = mq.QueueDeclare(broker.TRAFFICJOBQUEUE)
+	FailOnError(err, "Failed to declare Traffic Job Queue")
+
+	msgs, err = mq.Consume(trafficjobq.Name)
+	FailOnError(err, "Failed to register a consumer")
+}
+
+func processMsgs() {
+	for d := range msgs {
+		job := commonModels.TrafficWorkerQueueMessage{}
+		json.Unmarshal(d.Body, &job)
+		settings = job.WorldSettings
+		if job.PersonID.Valid() {
+			travel(job.PersonID)
+		}
+		reportDone(job.ReplyTo, job.PersonID)
+		d.Ack(false)
+	}
+}
+
+// reportDone tell the controller that queued a job it's finished
+func reportDone(replyTo string, person bson.ObjectId) {
+	if replyTo == "" {
+		return
+	}
+	msg, _ := json.Marshal(person)
+	err := mq.Publish(replyTo, msg)
+	FailOnError(err, "Failed to report job done")
+}
+
+// travel move a traveler one tick along toward their destination
+func travel(personID bson.ObjectId) {
+	person, err := store.GetPerson(Mongoid{ID: personID})
+	if err != nil {
+		LogToConsole("Failed to load traveler " + personID.Hex() + ": " + err.Error())
+		return
+	}
+	if !person.Traveling || !demographics.Alive(person) {
+		return
+	}
+	network, cities := roadGraph()
+	if len(person.Route) == 0 && !traffic.Plan(network, &person) {
+		LogToConsole("No road to " + person.Destination.String() + " for traveler " + person.ID.Hex() + ", heading straight there")
+	}
+	legs := traffic.Advance(settings, cities, worldTerrain(), &person)
+	if !person.Traveling && person.Responding.Valid() {
+		respond(&person)
+	}
+	rng := random.ForEntity(settings, person.ID, "accident")
+	if accident, crashed := traffic.Crash(rng, settings, &person, legs, settings.LastTime); crashed {
+		err = store.CreateAccident(accident)
+		FailOnError(err, "Failed to record accident")
+		if accident.Fatal {
+			LogToConsole(fmt.Sprintf("Fatal accident at %d mph: %s %s killed at %s", accident.Speed, person.FirstName, person.LastName, accident.Location))
+		} else {
+			LogToConsole(fmt.Sprintf("Accident at %d mph: %s %s injured at %s", accident.Speed, person.FirstName, person.LastName, accident.Location))
+		}
+	}
+	err = store.UpdatePerson(person)
+	FailOnError(err, "Failed to update traveler")
+}
+
+// respond an officer reaching a crime, afterwards they head back to the
+// station unless the crime was inside a building
+func respond(officer *commonModels.Person) {
+	crime, err := store.GetCrime(Mongoid{ID: officer.Responding})
+	if err != nil {
+		LogToConsole("Failed to load crime " + officer.Responding.Hex() + ": " + err.Error())
+		officer.Responding = ""
+		if !officer.CurrentBuilding.Valid() {
+			headBack(officer, officer.WorkBuilding)
+		}
+		return
+	}
+	rng := random.ForEntity(settings, officer.ID, "arrest")
+	if police.Respond(rng, officer, &crime, settings.LastTime) {
+		LogToConsole(fmt.Sprintf("Arrest made %d seconds after crime %s", crime.ResponseTime, crime.ID.Hex()))
+	}
+	err = store.UpdateCrime(crime)
+	FailOnError(err, "Failed to update crime")
+	if !crime.BuildingID.Valid() {
+		headBack(officer, crime.StationID)
+	}
+}
+
+// headBack send an officer out on the street to a building, their station
+// and then their home are tried if it can't be found so no building worker
+// ever loses track of them
+func headBack(officer *commonModels.Person, building bson.ObjectId) {
+	for _, id := range []bson.ObjectId{building, officer.WorkBuilding, officer.HomeBuilding} {
+		if !id.Valid() {
+			continue
+		}
+		destination, err := store.GetBuilding(Mongoid{ID: id})
+		if err != nil {
+			LogToConsole("Failed to load building " + id.Hex() + " for officer " + officer.ID.Hex() + ": " + err.Error())
+			continue
+		}
+		officer.Traveling = true
+		officer.Destination = destination.TopLeft
+		officer.DestinationID = destination.ID
+		return
+	}
+	LogToConsole("Officer " + officer.ID.Hex() + " has nowhere to go back to")
+}
+
+// roadGraph the road network and the cities it was built from, reloaded once
+// a simulated minute to pick up new cities, roads and speed limits
+func roadGraph() (*roads.Graph, []commonModels.City) {
+	minute := settings.Tick / 60
+	if graph == nil || minute != graphMinute {
+		cities, err := store.GetAllCities()
+		FailOnError(err, "Failed to load cities")
+		highways, err := store.GetAllHighways()
+		FailOnError(err, "Failed to load highways")
+		graph = traffic.NewGraph(settings, cities, highways)
+		graphCities = cities
+		graphMinute = minute
+	}
+	return graph, graphCities
+}
+
+// worldTerrain the terrain of the world the settings came from
+func worldTerrain() *terrain.Map {
+	if terrainMap == nil || terrainMap.Seed() != settings.Seed {
+		terrainMap = terrain.NewMap(settings, store)
+	}
+	return terrainMap
+}
+
+func main() {
+
+	id, _ := uuid.NewV4()
+	myself = commonModels.Worker{ID: id.String()}
+
+	InitLogger()
+	var err error
+	cfg, err = config.Load("traffic_worker", os.Args[1:])
+	FailOnError(err, "Failed to load config")
+	store = cfg.NewStore()
+	store.Connect()
+	connectQueues()
+	defer mq.Close()
+	go processMsgs()
+
+	go runConsole()
+
+	forever := make(chan bool)
+	fmt.Println("Ready")
+	<-forever
+}
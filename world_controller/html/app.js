@@ -0,0 +1,227 @@
+// DAWS dashboard, polls the world controller API and draws the map from
+// /api/map tiles
+(function () {
+    "use strict";
+
+    var TILE = 256;
+    var POLL = 2000;
+    // how often map tiles are fetched again to show travelers moving
+    var TILEREFRESH = 30000;
+
+    var world = null;
+    var view = { x: 0, y: 0, zoom: 9 };
+    var tileVersion = 0;
+    var tileTime = 0;
+    var centered = false;
+
+    function $(id) {
+        return document.getElementById(id);
+    }
+
+    function getJSON(url) {
+        return fetch(url).then(function (res) {
+            if (!res.ok) {
+                throw new Error(url + ": " + res.status);
+            }
+            return res.json();
+        });
+    }
+
+    function formatDuration(ms) {
+        if (ms >= 1000) {
+            return (ms / 1000).toFixed(2) + " s";
+        }
+        return ms.toFixed(1) + " ms";
+    }
+
+    function formatTime(value) {
+        var d = new Date(value);
+        if (isNaN(d.getTime())) {
+            return "-";
+        }
+        return d.toISOString().replace("T", " ").replace(/\.\d+Z$/, "");
+    }
+
+    function rows(body, items, columns) {
+        body.innerHTML = "";
+        items.forEach(function (item) {
+            var tr = document.createElement("tr");
+            columns(item).forEach(function (text) {
+                var td = document.createElement("td");
+                td.textContent = text;
+                tr.appendChild(td);
+            });
+            body.appendChild(tr);
+        });
+    }
+
+    // status panel
+
+    function showStatus(s) {
+        var state = $("state");
+        state.textContent = s.runTrigger ? "running" : "stopped";
+        state.className = "state " + (s.runTrigger ? "running" : "stopped");
+        $("simTime").textContent = formatTime(s.lastTime);
+        $("realTime").textContent = formatTime(s.realTime);
+        $("tick").textContent = s.tick;
+        $("tickRate").textContent = s.ticksPerSecond.toFixed(1) + " /s";
+        $("lastTick").textContent = formatDuration(s.lastTickMs);
+        $("worldSpeed").textContent = s.worldSpeed + " ms";
+        ["cities", "buildings", "people"].forEach(function (name) {
+            $(name).textContent = s.counts.error ? "-" : s.counts[name];
+            $(name).title = s.counts.error || "";
+        });
+        rows($("queues"), s.queues, function (q) {
+            return [q.name, q.error ? q.error : q.messages, q.error ? "-" : q.consumers];
+        });
+        rows($("controllers"), s.controllers, function (c) {
+            return [c.type, c.id.slice(0, 8), c.ready ? "yes" : "no"];
+        });
+    }
+
+    function showWorld(w) {
+        world = w;
+        if (view.zoom > w.maxZoom) {
+            view.zoom = w.maxZoom;
+            $("tiles").innerHTML = "";
+        }
+        var list = $("cityList");
+        list.innerHTML = "";
+        w.cities.forEach(function (city) {
+            var li = document.createElement("li");
+            var a = document.createElement("a");
+            a.textContent = city.name;
+            a.onclick = function () {
+                centerOn(city);
+            };
+            li.appendChild(a);
+            list.appendChild(li);
+        });
+        if (!centered && w.cities.length > 0) {
+            centerOn(w.cities[0]);
+        }
+    }
+
+    function poll() {
+        Promise.all([getJSON("api/status"), getJSON("api/world")]).then(function (results) {
+            showStatus(results[0]);
+            showWorld(results[1]);
+            if (Date.now() - tileTime >= TILEREFRESH) {
+                tileVersion = results[0].tick;
+                tileTime = Date.now();
+            }
+            drawMap();
+        }).catch(function (err) {
+            $("state").textContent = "offline";
+            $("state").className = "state stopped";
+            console.log(err);
+        }).then(function () {
+            setTimeout(poll, POLL);
+        });
+    }
+
+    // map, view.x and view.y are the world feet at the center of the map
+
+    function feetPerPixel() {
+        return (world.bounds.bottomright.X - world.bounds.topleft.X) / (TILE * Math.pow(2, view.zoom));
+    }
+
+    function centerOn(city) {
+        view.x = (city.topleft.X + city.bottomright.X) / 2;
+        view.y = (city.topleft.Y + city.bottomright.Y) / 2;
+        centered = true;
+        drawMap();
+    }
+
+    function drawMap() {
+        if (!world) {
+            return;
+        }
+        var map = $("map");
+        var tiles = $("tiles");
+        var width = map.clientWidth;
+        var height = map.clientHeight;
+        var scale = feetPerPixel();
+        var n = Math.pow(2, view.zoom);
+        // world pixel at the top left of the map
+        var left = (view.x - world.bounds.topleft.X) / scale - width / 2;
+        var top = (view.y - world.bounds.topleft.Y) / scale - height / 2;
+
+        var wanted = {};
+        for (var ty = Math.max(0, Math.floor(top / TILE)); ty < n && ty * TILE < top + height; ty++) {
+            for (var tx = Math.max(0, Math.floor(left / TILE)); tx < n && tx * TILE < left + width; tx++) {
+                wanted[view.zoom + "/" + tx + "/" + ty] = { x: tx * TILE - left, y: ty * TILE - top };
+            }
+        }
+
+        Array.prototype.slice.call(tiles.children).forEach(function (img) {
+            if (!wanted[img.dataset.key]) {
+                tiles.removeChild(img);
+            }
+        });
+        Object.keys(wanted).forEach(function (key) {
+            var img = tiles.querySelector('img[data-key="' + key + '"]');
+            if (!img) {
+                img = document.createElement("img");
+                img.dataset.key = key;
+                img.draggable = false;
+                tiles.appendChild(img);
+            }
+            var src = "api/map/" + key + ".png?v=" + tileVersion;
+            if (img.getAttribute("src") !== src) {
+                img.setAttribute("src", src);
+            }
+            img.style.left = Math.round(wanted[key].x) + "px";
+            img.style.top = Math.round(wanted[key].y) + "px";
+        });
+    }
+
+    function zoom(delta) {
+        if (!world) {
+            return;
+        }
+        var z = Math.min(world.maxZoom, Math.max(0, view.zoom + delta));
+        if (z !== view.zoom) {
+            view.zoom = z;
+            $("tiles").innerHTML = "";
+            drawMap();
+        }
+    }
+
+    function setupMap() {
+        var map = $("map");
+        var drag = null;
+        map.addEventListener("mousedown", function (e) {
+            drag = { x: e.clientX, y: e.clientY };
+            map.classList.add("dragging");
+        });
+        window.addEventListener("mousemove", function (e) {
+            if (!drag || !world) {
+                return;
+            }
+            var scale = feetPerPixel();
+            view.x -= (e.clientX - drag.x) * scale;
+            view.y -= (e.clientY - drag.y) * scale;
+            drag = { x: e.clientX, y: e.clientY };
+            drawMap();
+        });
+        window.addEventListener("mouseup", function () {
+            drag = null;
+            map.classList.remove("dragging");
+        });
+        map.addEventListener("wheel", function (e) {
+            e.preventDefault();
+            zoom(e.deltaY < 0 ? 1 : -1);
+        });
+        $("zoomIn").onclick = function () {
+            zoom(1);
+        };
+        $("zoomOut").onclick = function () {
+            zoom(-1);
+        };
+        window.addEventListener("resize", drawMap);
+    }
+
+    setupMap();
+    poll();
+})();